@@ -0,0 +1,186 @@
+package op
+
+import (
+	"context"
+	"time"
+)
+
+// WithBackgroundChecks makes each checker run on its own schedule in the
+// background, every interval, instead of on every call to Check. Check and the
+// health endpoint then serve the last cached result of each checker. Results
+// older than maxAge are flagged as stale, a zero maxAge disables this.
+// Background checks only run between calls to Start and Stop.
+func (s *Status) WithBackgroundChecks(interval, maxAge time.Duration) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.backgroundInterval = interval
+	s.backgroundMaxAge = maxAge
+	return s
+}
+
+// CheckerInterval sets how often the checker is run when background checks
+// are enabled, overriding the interval given to WithBackgroundChecks.
+func CheckerInterval(d time.Duration) CheckerOption {
+	return func(ch *checker) {
+		ch.interval = d
+	}
+}
+
+// Start begins running the checkers in the background, if background checks
+// have been enabled with WithBackgroundChecks. They keep running until ctx is
+// cancelled or Stop is called. Checkers added while running are started
+// straight away.
+func (s *Status) Start(ctx context.Context) {
+	s.backgroundMu.Lock()
+	defer s.backgroundMu.Unlock()
+
+	s.mu.RLock()
+	interval, checkers := s.backgroundInterval, s.checkers
+	s.mu.RUnlock()
+
+	if interval <= 0 || s.backgroundCtx != nil {
+		return
+	}
+	s.backgroundCtx, s.backgroundCancel = context.WithCancel(ctx)
+	for _, ch := range checkers {
+		s.startBackgroundCheckerLocked(ch)
+	}
+}
+
+// Stop stops the background checkers started by Start and waits for them to
+// return. Cached results are kept, and will become stale.
+func (s *Status) Stop() {
+	s.backgroundMu.Lock()
+	defer s.backgroundMu.Unlock()
+
+	if s.backgroundCancel == nil {
+		return
+	}
+	s.backgroundCancel()
+	s.backgroundCtx, s.backgroundCancel = nil, nil
+	s.backgroundWG.Wait()
+
+	s.mu.RLock()
+	checkers := s.checkers
+	s.mu.RUnlock()
+
+	for _, ch := range checkers {
+		ch.mu.Lock()
+		ch.stop = nil
+		ch.mu.Unlock()
+	}
+}
+
+func (s *Status) startBackgroundChecker(ch *checker) {
+	s.backgroundMu.Lock()
+	defer s.backgroundMu.Unlock()
+	s.startBackgroundCheckerLocked(ch)
+}
+
+func (s *Status) startBackgroundCheckerLocked(ch *checker) {
+	if s.backgroundCtx == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.backgroundCtx)
+	ch.mu.Lock()
+	if ch.stop != nil {
+		// Already running, having been started by both Start and
+		// AddCheckerContext.
+		ch.mu.Unlock()
+		cancel()
+		return
+	}
+	ch.stop = cancel
+	ch.mu.Unlock()
+
+	interval := ch.interval
+	if interval <= 0 {
+		s.mu.RLock()
+		interval = s.backgroundInterval
+		s.mu.RUnlock()
+	}
+
+	s.backgroundWG.Add(1)
+	go func() {
+		defer s.backgroundWG.Done()
+		s.runBackground(ctx, ch, interval)
+	}()
+}
+
+func (s *Status) stopBackgroundCheckers(checkers []*checker) {
+	s.backgroundMu.Lock()
+	defer s.backgroundMu.Unlock()
+
+	for _, ch := range checkers {
+		ch.mu.Lock()
+		if ch.stop != nil {
+			ch.stop()
+		}
+		ch.mu.Unlock()
+	}
+}
+
+func (s *Status) runBackground(ctx context.Context, ch *checker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ch.mu.Lock()
+		running, runningSince := ch.running > 0, ch.runningSince
+		ch.mu.Unlock()
+
+		var e healthResultEntry
+		if running {
+			// A previous run timed out but hasn't returned. Don't pile up
+			// more goroutines behind a check that ignores its context, and
+			// record how long it has been running so far as its duration.
+			var cr CheckResponse
+			cr.Unhealthy("previous run still in progress", "", "")
+			e = s.recordCheck(ch, cr, time.Now(), time.Since(runningSince), false)
+		} else {
+			var recorded bool
+			e, recorded = s.runCheck(ctx, ch)
+			if !recorded {
+				// Don't cache the result of a check we cancelled ourselves.
+				return
+			}
+		}
+
+		ch.mu.Lock()
+		ch.cached = &e
+		ch.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cachedResult returns the last result of a checker run in the background.
+func (s *Status) cachedResult(ch *checker) healthResultEntry {
+	ch.mu.Lock()
+	cached := ch.cached
+	ch.mu.Unlock()
+
+	if cached == nil {
+		return healthResultEntry{
+			Name:   ch.name,
+			Health: unhealthy,
+			Output: "check has not run yet",
+		}
+	}
+
+	s.mu.RLock()
+	maxAge := s.backgroundMaxAge
+	s.mu.RUnlock()
+
+	e := *cached
+	if maxAge > 0 && time.Since(*e.LastRun) > maxAge {
+		e.Stale = true
+	}
+	return e
+}
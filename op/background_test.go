@@ -0,0 +1,163 @@
+package op
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	hc := NewStatus("my app", "app description").
+		WithBackgroundChecks(5*time.Millisecond, 0).
+		AddChecker("counter", func(cr *CheckResponse) {
+			atomic.AddInt32(&calls, 1)
+			cr.Healthy("counted")
+		})
+
+	result := hc.Check()
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("check has not run yet", result.CheckResults[0].Output)
+	assert.Zero(atomic.LoadInt32(&calls), "checker should not run before Start")
+
+	hc.Start(context.Background())
+	assert.Eventually(func() bool { return atomic.LoadInt32(&calls) >= 3 }, time.Second, time.Millisecond)
+
+	result = hc.Check()
+	assert.Equal("healthy", result.Health)
+	assert.Equal("counted", result.CheckResults[0].Output)
+	assert.NotNil(result.CheckResults[0].LastRun)
+	assert.False(result.CheckResults[0].Stale)
+
+	hc.Stop()
+	stopped := atomic.LoadInt32(&calls)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(stopped, atomic.LoadInt32(&calls), "checker should not run after Stop")
+}
+
+func TestBackgroundChecksStale(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").
+		WithBackgroundChecks(time.Hour, 10*time.Millisecond).
+		AddChecker("slow", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		})
+
+	hc.Start(context.Background())
+	defer hc.Stop()
+
+	assert.Eventually(func() bool { return hc.Check().Health == healthy }, time.Second, time.Millisecond)
+	assert.Eventually(func() bool { return hc.Check().CheckResults[0].Stale }, time.Second, time.Millisecond)
+}
+
+func TestBackgroundChecksAddRemoveWhileRunning(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	hc := NewStatus("my app", "app description").
+		WithBackgroundChecks(5*time.Millisecond, 0)
+	hc.Start(ctx)
+	defer hc.Stop()
+
+	hc.AddChecker("late", func(cr *CheckResponse) {
+		atomic.AddInt32(&calls, 1)
+		cr.Healthy("ok")
+	}, CheckerInterval(time.Millisecond))
+	assert.Eventually(func() bool { return atomic.LoadInt32(&calls) >= 2 }, time.Second, time.Millisecond)
+
+	hc.RemoveCheckers("late")
+	time.Sleep(10 * time.Millisecond)
+	removed := atomic.LoadInt32(&calls)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(removed, atomic.LoadInt32(&calls), "removed checker should stop running")
+}
+
+func TestBackgroundChecksStopDoesNotRecordCancelledRun(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan struct{})
+	hc := NewStatus("my app", "app description").
+		WithBackgroundChecks(time.Hour, 0).
+		AddCheckerContext("blocking", func(ctx context.Context, cr *CheckResponse) {
+			close(started)
+			<-ctx.Done()
+			cr.Healthy("done")
+		})
+	hc.checkResultGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: healthcheckStatus,
+	}, []string{healthcheckName, healthcheckResult})
+
+	hc.Start(context.Background())
+	<-started
+	hc.Stop()
+
+	assert.Equal(float64(0), testutil.ToFloat64(hc.checkResultGauge.WithLabelValues("blocking", unhealthy)))
+	assert.Equal("check has not run yet", hc.Check().CheckResults[0].Output)
+}
+
+func TestBackgroundChecksSkipWhilePreviousRunInProgress(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	release := make(chan struct{})
+	hc := NewStatus("my app", "app description").
+		WithBackgroundChecks(5*time.Millisecond, 0).
+		AddChecker("hanging", func(cr *CheckResponse) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-release
+			}
+			cr.Healthy("ok")
+		}, CheckerTimeout(time.Millisecond))
+
+	hc.Start(context.Background())
+	defer hc.Stop()
+
+	assert.Eventually(func() bool {
+		return hc.Check().CheckResults[0].Output == "previous run still in progress"
+	}, time.Second, time.Millisecond)
+	assert.Equal(int32(1), atomic.LoadInt32(&calls), "checker should not be run again while hanging")
+
+	close(release)
+	assert.Eventually(func() bool { return hc.Check().Health == healthy }, time.Second, time.Millisecond)
+}
+
+func TestBackgroundChecksInProgressDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	hc := NewStatus("my app", "app description").
+		WithBackgroundChecks(5*time.Millisecond, 0).
+		WithCheckHistory(100).
+		AddChecker("hanging", func(cr *CheckResponse) {
+			<-release
+		}, CheckerTimeout(time.Millisecond))
+	hc.checkDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: healthcheckDuration,
+	}, []string{healthcheckName})
+
+	hc.Start(context.Background())
+	assert.Eventually(func() bool {
+		return len(hc.History().Checks[0].History) >= 4
+	}, time.Second, time.Millisecond)
+	hc.Stop()
+
+	var last float64
+	for _, he := range hc.History().Checks[0].History[1:] {
+		assert.Equal("previous run still in progress", he.Output)
+		assert.Greater(he.Duration, last, "duration should grow while the run is hung")
+		last = he.Duration
+	}
+	assert.GreaterOrEqual(histogramSampleSum(t, hc.checkDurationHistogram), last)
+}
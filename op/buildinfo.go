@@ -0,0 +1,79 @@
+package op
+
+import (
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const buildInfoMetric = "build_info"
+
+// readBuildInfo is replaced in tests, as test binaries carry no VCS details.
+var readBuildInfo = debug.ReadBuildInfo
+
+// WithBuildInfo fills the build info reported by About from the information
+// embedded in the binary by the Go toolchain: the VCS revision and time,
+// whether the working tree was modified, the Go version and the main module
+// path and version. The versions of the given dependency module paths are
+// included too. A revision set with SetRevision takes precedence.
+func (s *Status) WithBuildInfo(dependencies ...string) *Status {
+	bi, ok := readBuildInfo()
+	if !ok {
+		return s
+	}
+
+	info := buildInfoResponse{
+		GoVersion: bi.GoVersion,
+		Path:      bi.Main.Path,
+		Version:   bi.Main.Version,
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	for _, dep := range bi.Deps {
+		for _, path := range dependencies {
+			if dep.Path != path {
+				continue
+			}
+			if info.Dependencies == nil {
+				info.Dependencies = map[string]string{}
+			}
+			if dep.Replace != nil {
+				info.Dependencies[path] = dep.Replace.Version
+			} else {
+				info.Dependencies[path] = dep.Version
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buildInfo = &info
+	return s
+}
+
+// WithBuildInfoMetric registers a build_info gauge, always 1, labelled with
+// the revision, version and Go version reported by About. It should be called
+// after SetRevision or WithBuildInfo.
+func (s *Status) WithBuildInfoMetric() *Status {
+	info := s.About().BuildInfo
+	g := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: buildInfoMetric,
+		Help: "Build information about the application, always 1",
+		ConstLabels: prometheus.Labels{
+			"revision":   info.Revision,
+			"version":    info.Version,
+			"go_version": info.GoVersion,
+		},
+	})
+	g.Set(1)
+	s.register(g)
+	return s
+}
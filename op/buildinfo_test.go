@@ -0,0 +1,58 @@
+package op
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(f func() (*debug.BuildInfo, bool)) { readBuildInfo = f }(readBuildInfo)
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.22.0",
+			Main:      debug.Module{Path: "github.com/example/app", Version: "v1.2.3"},
+			Deps: []*debug.Module{
+				{Path: "github.com/example/lib", Version: "v0.1.0"},
+				{Path: "github.com/example/forked", Version: "v1.0.0", Replace: &debug.Module{Path: "github.com/fork/forked", Version: "v1.0.1"}},
+				{Path: "github.com/example/ignored", Version: "v9.9.9"},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "abc123"},
+				{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+
+	hc := NewStatus("my app", "app description").WithBuildInfo("github.com/example/lib", "github.com/example/forked")
+	assert.Equal(buildInfoResponse{
+		Revision:  "abc123",
+		Time:      "2024-01-02T03:04:05Z",
+		Modified:  true,
+		GoVersion: "go1.22.0",
+		Path:      "github.com/example/app",
+		Version:   "v1.2.3",
+		Dependencies: map[string]string{
+			"github.com/example/lib":    "v0.1.0",
+			"github.com/example/forked": "v1.0.1",
+		},
+	}, hc.About().BuildInfo)
+
+	hc.SetRevision("manual")
+	assert.Equal("manual", hc.About().BuildInfo.Revision)
+
+	reg := prometheus.NewRegistry()
+	hc.WithRegistry(reg, reg).WithBuildInfoMetric()
+	assert.NoError(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP build_info Build information about the application, always 1
+# TYPE build_info gauge
+build_info{go_version="go1.22.0",revision="manual",version="v1.2.3"} 1
+`), buildInfoMetric))
+}
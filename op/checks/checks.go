@@ -0,0 +1,104 @@
+// Package checks provides ready made checkers for common dependencies, for
+// use with op.Status.AddCheckerContext.
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/utilitywarehouse/go-operational/op"
+)
+
+// Pinger is implemented by anything that can check its connection, such as
+// *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SQL returns a checker that pings a database, typically a *sql.DB.
+func SQL(db Pinger) func(ctx context.Context, cr *op.CheckResponse) {
+	return func(ctx context.Context, cr *op.CheckResponse) {
+		if err := db.PingContext(ctx); err != nil {
+			cr.Unhealthy(
+				fmt.Sprintf("failed to ping database: %v", err),
+				"check the database is up and reachable",
+				"data cannot be read or written",
+			)
+			return
+		}
+		cr.Healthy("database ping succeeded")
+	}
+}
+
+// TCP returns a checker that opens, and then closes, a TCP connection to addr.
+func TCP(addr string) func(ctx context.Context, cr *op.CheckResponse) {
+	return func(ctx context.Context, cr *op.CheckResponse) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			cr.Unhealthy(
+				fmt.Sprintf("failed to connect to %s: %v", addr, err),
+				fmt.Sprintf("check %s is up and reachable", addr),
+				fmt.Sprintf("requests depending on %s will fail", addr),
+			)
+			return
+		}
+		conn.Close()
+		cr.Healthy(fmt.Sprintf("connected to %s", addr))
+	}
+}
+
+// HTTP returns a checker that makes a GET request to url and expects a
+// response with the given status code.
+func HTTP(url string, expectedStatus int) func(ctx context.Context, cr *op.CheckResponse) {
+	return func(ctx context.Context, cr *op.CheckResponse) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			cr.Unhealthy(
+				fmt.Sprintf("invalid request to %s: %v", url, err),
+				"fix the URL of the check",
+				"the health of this dependency is not known",
+			)
+			return
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cr.Unhealthy(
+				fmt.Sprintf("GET %s failed: %v", url, err),
+				fmt.Sprintf("check %s is up and reachable", url),
+				fmt.Sprintf("requests depending on %s will fail", url),
+			)
+			return
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != expectedStatus {
+			cr.Unhealthy(
+				fmt.Sprintf("GET %s returned status %d, expected %d", url, resp.StatusCode, expectedStatus),
+				fmt.Sprintf("check the logs of the service behind %s", url),
+				fmt.Sprintf("requests depending on %s may fail", url),
+			)
+			return
+		}
+		cr.Healthy(fmt.Sprintf("GET %s returned status %d", url, resp.StatusCode))
+	}
+}
+
+// DNS returns a checker that resolves host.
+func DNS(host string) func(ctx context.Context, cr *op.CheckResponse) {
+	return func(ctx context.Context, cr *op.CheckResponse) {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			cr.Unhealthy(
+				fmt.Sprintf("failed to resolve %s: %v", host, err),
+				"check DNS resolution is working",
+				fmt.Sprintf("%s cannot be reached", host),
+			)
+			return
+		}
+		cr.Healthy(fmt.Sprintf("%s resolved to %v", host, addrs))
+	}
+}
@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/utilitywarehouse/go-operational/op"
+)
+
+func runCheck(checkerFunc func(ctx context.Context, cr *op.CheckResponse)) (health, output string) {
+	result := op.NewStatus("", "").AddCheckerContext("check", checkerFunc).Check()
+	return result.CheckResults[0].Health, result.CheckResults[0].Output
+}
+
+type fakePinger struct {
+	err error
+}
+
+func (p fakePinger) PingContext(ctx context.Context) error {
+	return p.err
+}
+
+func TestSQL(t *testing.T) {
+	assert := assert.New(t)
+
+	health, output := runCheck(SQL(fakePinger{}))
+	assert.Equal("healthy", health)
+	assert.Equal("database ping succeeded", output)
+
+	health, output = runCheck(SQL(fakePinger{err: errors.New("connection refused")}))
+	assert.Equal("unhealthy", health)
+	assert.Equal("failed to ping database: connection refused", output)
+}
+
+func TestTCP(t *testing.T) {
+	assert := assert.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+
+	health, output := runCheck(TCP(addr))
+	assert.Equal("healthy", health)
+	assert.Equal("connected to "+addr, output)
+
+	l.Close()
+
+	health, _ = runCheck(TCP(addr))
+	assert.Equal("unhealthy", health)
+}
+
+func TestHTTP(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	health, output := runCheck(HTTP(srv.URL+"/ok", http.StatusOK))
+	assert.Equal("healthy", health)
+	assert.Equal("GET "+srv.URL+"/ok returned status 200", output)
+
+	health, output = runCheck(HTTP(srv.URL+"/broken", http.StatusOK))
+	assert.Equal("unhealthy", health)
+	assert.Equal("GET "+srv.URL+"/broken returned status 500, expected 200", output)
+
+	srv.Close()
+
+	health, _ = runCheck(HTTP(srv.URL+"/ok", http.StatusOK))
+	assert.Equal("unhealthy", health)
+}
+
+func TestDNS(t *testing.T) {
+	assert := assert.New(t)
+
+	health, _ := runCheck(DNS("localhost"))
+	assert.Equal("healthy", health)
+
+	health, _ = runCheck(DNS("does-not-exist.invalid"))
+	assert.Equal("unhealthy", health)
+}
+
+func TestDiskFree(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+
+	health, _ := runCheck(DiskFree(dir, 0, 0))
+	assert.Equal("healthy", health)
+
+	health, _ = runCheck(DiskFree(dir, math.MaxUint64, 0))
+	assert.Equal("degraded", health)
+
+	health, _ = runCheck(DiskFree(dir, math.MaxUint64, math.MaxUint64))
+	assert.Equal("unhealthy", health)
+
+	health, _ = runCheck(DiskFree(dir+"/missing", 0, 0))
+	assert.Equal("unhealthy", health)
+}
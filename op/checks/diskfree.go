@@ -0,0 +1,40 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/utilitywarehouse/go-operational/op"
+)
+
+// DiskFree returns a checker that reports degraded once the free space
+// available on the filesystem containing path drops below degradedBelow
+// bytes, and unhealthy once it drops below unhealthyBelow bytes.
+func DiskFree(path string, degradedBelow, unhealthyBelow uint64) func(ctx context.Context, cr *op.CheckResponse) {
+	return func(ctx context.Context, cr *op.CheckResponse) {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(path, &st); err != nil {
+			cr.Unhealthy(
+				fmt.Sprintf("failed to stat filesystem of %s: %v", path, err),
+				fmt.Sprintf("check %s exists and is mounted", path),
+				"data cannot be written to disk",
+			)
+			return
+		}
+
+		free := uint64(st.Bavail) * uint64(st.Bsize)
+		output := fmt.Sprintf("%d bytes free on %s", free, path)
+		switch {
+		case free < unhealthyBelow:
+			cr.Unhealthy(output, fmt.Sprintf("free up space on %s", path), "data cannot be written to disk")
+		case free < degradedBelow:
+			cr.Degraded(output, fmt.Sprintf("free up space on %s", path))
+		default:
+			cr.Healthy(output)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package checks
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/utilitywarehouse/go-operational/op"
+)
+
+// DiskFree returns a checker that reports degraded once the free space
+// available on the filesystem containing path drops below degradedBelow
+// bytes, and unhealthy once it drops below unhealthyBelow bytes.
+//
+// It is not supported on this platform and always reports unhealthy.
+func DiskFree(path string, degradedBelow, unhealthyBelow uint64) func(ctx context.Context, cr *op.CheckResponse) {
+	return func(ctx context.Context, cr *op.CheckResponse) {
+		cr.Unhealthy(
+			"disk free space checks are not supported on "+runtime.GOOS,
+			"remove the check",
+			"the health of this dependency is not known",
+		)
+	}
+}
@@ -0,0 +1,113 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/utilitywarehouse/go-operational/op"
+)
+
+const defaultDownstreamTimeout = 5 * time.Second
+
+// DownstreamOption configures a checker returned by Downstream.
+type DownstreamOption func(*downstream)
+
+// DownstreamTimeout sets how long to wait for the downstream health check,
+// five seconds by default.
+func DownstreamTimeout(d time.Duration) DownstreamOption {
+	return func(ds *downstream) {
+		ds.timeout = d
+	}
+}
+
+// DownstreamCheckNames includes the name and health of each of the downstream
+// service's checks in the output.
+func DownstreamCheckNames() DownstreamOption {
+	return func(ds *downstream) {
+		ds.checkNames = true
+	}
+}
+
+type downstream struct {
+	url        string
+	timeout    time.Duration
+	checkNames bool
+}
+
+// Downstream returns a checker that fetches the health of another service
+// exposing the operational endpoints, from url, typically ending in
+// "/__/health". The downstream service's overall health becomes the health of
+// this check.
+func Downstream(url string, opts ...DownstreamOption) func(ctx context.Context, cr *op.CheckResponse) {
+	ds := &downstream{url: url, timeout: defaultDownstreamTimeout}
+	for _, opt := range opts {
+		opt(ds)
+	}
+	return ds.check
+}
+
+func (ds *downstream) check(ctx context.Context, cr *op.CheckResponse) {
+	action := fmt.Sprintf("check the health of the service at %s", ds.url)
+
+	hr, err := ds.fetch(ctx)
+	if err != nil {
+		cr.Unhealthy(
+			fmt.Sprintf("failed to get health from %s: %v", ds.url, err),
+			action,
+			"requests depending on this service will fail",
+		)
+		return
+	}
+
+	output := fmt.Sprintf("%s is %s", hr.Name, hr.Health)
+	if ds.checkNames && len(hr.CheckResults) > 0 {
+		var names []string
+		for _, e := range hr.CheckResults {
+			names = append(names, fmt.Sprintf("%s (%s)", e.Name, e.Health))
+		}
+		output += ": " + strings.Join(names, ", ")
+	}
+
+	switch hr.Health {
+	case "healthy":
+		cr.Healthy(output)
+	case "degraded":
+		cr.Degraded(output, action)
+	default:
+		cr.Unhealthy(output, action, "requests depending on this service will fail")
+	}
+}
+
+func (ds *downstream) fetch(ctx context.Context) (op.HealthResult, error) {
+	var hr op.HealthResult
+
+	if ds.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ds.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ds.url, nil)
+	if err != nil {
+		return hr, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return hr, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return hr, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&hr); err != nil {
+		return hr, fmt.Errorf("invalid health response: %w", err)
+	}
+	return hr, nil
+}
@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/utilitywarehouse/go-operational/op"
+)
+
+func TestDownstream(t *testing.T) {
+	assert := assert.New(t)
+
+	var remoteHealth string
+	remote := op.NewStatus("remote", "remote service").
+		AddChecker("db", func(cr *op.CheckResponse) {
+			cr.Healthy("ok")
+		}).
+		AddChecker("cache", func(cr *op.CheckResponse) {
+			switch remoteHealth {
+			case "degraded":
+				cr.Degraded("slow", "warm the cache")
+			case "unhealthy":
+				cr.Unhealthy("down", "restart the cache", "everything is slow")
+			default:
+				cr.Healthy("ok")
+			}
+		})
+	srv := httptest.NewServer(op.NewHandler(remote))
+	defer srv.Close()
+
+	health, output := runCheck(Downstream(srv.URL + "/__/health"))
+	assert.Equal("healthy", health)
+	assert.Equal("remote is healthy", output)
+
+	remoteHealth = "degraded"
+	health, output = runCheck(Downstream(srv.URL+"/__/health", DownstreamCheckNames()))
+	assert.Equal("degraded", health)
+	assert.Equal("remote is degraded: db (healthy), cache (degraded)", output)
+
+	remoteHealth = "unhealthy"
+	health, _ = runCheck(Downstream(srv.URL + "/__/health"))
+	assert.Equal("unhealthy", health)
+
+	health, output = runCheck(Downstream(srv.URL + "/__/missing"))
+	assert.Equal("unhealthy", health)
+	assert.Equal("failed to get health from "+srv.URL+"/__/missing: unexpected status 404", output)
+}
+
+func TestDownstreamTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	health, output := runCheck(Downstream(srv.URL, DownstreamTimeout(10*time.Millisecond)))
+	assert.Less(time.Since(start), time.Second)
+	assert.Equal("unhealthy", health)
+	assert.True(strings.Contains(output, context.DeadlineExceeded.Error()), output)
+}
@@ -0,0 +1,83 @@
+package op
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConcurrentMutation changes the status while it is being served. It is
+// most useful when run with the race detector.
+func TestConcurrentMutation(t *testing.T) {
+	st := NewStatus("my app", "app description").
+		WithCheckHistory(5).
+		AddChecker("static", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		})
+	h := NewHandler(st)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	run := func(f func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ctx.Err() == nil; i++ {
+				f(i)
+			}
+		}()
+	}
+
+	for _, path := range []string{"/__/health", "/__/ready", "/__/live", "/__/about", "/__/health/history"} {
+		path := path
+		run(func(int) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+		})
+	}
+
+	run(func(i int) {
+		name := fmt.Sprintf("tenant-%d", i%3)
+		st.AddChecker(name, func(cr *CheckResponse) {
+			cr.Healthy("tenant ok")
+		})
+		st.RemoveCheckers(name)
+	})
+	run(func(i int) {
+		switch i % 3 {
+		case 0:
+			st.ReadyAlways()
+		case 1:
+			st.ReadyUseHealthCheck()
+		case 2:
+			st.ReadyNone()
+		}
+		st.AddReadyChecker("warm", func(ctx context.Context, cr *CheckResponse) {
+			cr.Healthy("warm")
+		})
+		st.RemoveReadyCheckers("warm")
+	})
+	run(func(i int) {
+		st.AddLiveChecker("goroutines", GoroutineChecker(1e6))
+		st.RemoveLiveCheckers("goroutines")
+		st.WithCheckTimeout(time.Duration(i%2) * time.Second)
+	})
+	run(func(i int) {
+		if i%2 == 0 {
+			st.WithBackgroundChecks(time.Millisecond, 0)
+			st.Start(ctx)
+		} else {
+			st.Stop()
+			st.WithBackgroundChecks(0, 0)
+		}
+	})
+
+	wg.Wait()
+	st.Stop()
+}
@@ -0,0 +1,48 @@
+package op
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDegraded can be wrapped by errors returned from a function passed to
+// ErrorChecker to report degraded rather than unhealthy health, for example
+// with fmt.Errorf("replica lag is high: %w", op.ErrDegraded).
+var ErrDegraded = errors.New("degraded")
+
+// DegradedError wraps an error returned from a function passed to
+// ErrorChecker to report degraded rather than unhealthy health.
+type DegradedError struct {
+	Err error
+}
+
+func (e *DegradedError) Error() string {
+	if e.Err == nil {
+		return ErrDegraded.Error()
+	}
+	return e.Err.Error()
+}
+
+func (e *DegradedError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorChecker adapts a function returning an error into a checker, for use
+// with AddCheckerContext. A nil error reports healthy. An error wrapping
+// ErrDegraded, or a *DegradedError, reports degraded with the given action.
+// Any other error reports unhealthy with the given action and impact. The
+// error is used as the output.
+func ErrorChecker(check func(ctx context.Context) error, action, impact string) func(ctx context.Context, cr *CheckResponse) {
+	return func(ctx context.Context, cr *CheckResponse) {
+		err := check(ctx)
+		var de *DegradedError
+		switch {
+		case err == nil:
+			cr.Healthy("check succeeded")
+		case errors.Is(err, ErrDegraded), errors.As(err, &de):
+			cr.Degraded(err.Error(), action)
+		default:
+			cr.Unhealthy(err.Error(), action, impact)
+		}
+	}
+}
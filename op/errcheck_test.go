@@ -0,0 +1,53 @@
+package op
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorChecker(t *testing.T) {
+	assert := assert.New(t)
+
+	check := func(err error) healthResultEntry {
+		return NewStatus("my app", "app description").
+			AddCheckerContext("check", ErrorChecker(func(ctx context.Context) error {
+				return err
+			}, "fix the thing", "users cannot do the thing")).
+			Check().CheckResults[0]
+	}
+
+	assert.Equal(healthResultEntry{Name: "check", Health: "healthy", Output: "check succeeded"}, check(nil))
+
+	assert.Equal(healthResultEntry{
+		Name:   "check",
+		Health: "degraded",
+		Output: "replica lag is high: degraded",
+		Action: "fix the thing",
+	}, check(fmt.Errorf("replica lag is high: %w", ErrDegraded)))
+
+	assert.Equal(healthResultEntry{
+		Name:   "check",
+		Health: "degraded",
+		Output: "cache: cache miss rate is high",
+		Action: "fix the thing",
+	}, check(fmt.Errorf("cache: %w", &DegradedError{Err: errors.New("cache miss rate is high")})))
+
+	assert.Equal(healthResultEntry{
+		Name:   "check",
+		Health: "degraded",
+		Output: "degraded",
+		Action: "fix the thing",
+	}, check(&DegradedError{}))
+
+	assert.Equal(healthResultEntry{
+		Name:   "check",
+		Health: "unhealthy",
+		Output: "connection refused",
+		Action: "fix the thing",
+		Impact: "users cannot do the thing",
+	}, check(errors.New("connection refused")))
+}
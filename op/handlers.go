@@ -6,81 +6,302 @@ import (
 	"log"
 	"net/http"
 	"net/http/pprof"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func newHealthCheckHandler(hc *Status) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if len(hc.checkers) == 0 {
+		if !hc.hasCheckers() {
 			http.NotFound(w, r)
 			return
 		}
 		w.Header().Add("Content-Type", "application/json")
-		if err := newEncoder(w).Encode(hc.Check()); err != nil {
+		if err := newEncoder(w).Encode(hc.CheckContext(r.Context())); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 	})
 }
 
+func newHistoryHandler(hc *Status) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hc.historyEnabled() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		if err := newEncoder(w).Encode(hc.History()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+}
+
+func newOverrideHandler(hc *Status) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hc.mu.RLock()
+		authorize := hc.overrideAuthorize
+		hc.mu.RUnlock()
+
+		if authorize == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !authorize(r) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var or overrideRequest
+		r.Body = http.MaxBytesReader(w, r.Body, maxOverrideRequestSize)
+		if err := json.NewDecoder(r.Body).Decode(&or); err != nil {
+			http.Error(w, fmt.Sprintf("invalid override request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case or.Drain != nil && *or.Drain:
+			hc.Drain(or.Reason)
+		case or.Drain != nil:
+			hc.Undrain()
+		case or.Clear:
+			hc.ClearOverride(or.Check)
+		case or.Expires.IsZero():
+			// An override that is forgotten about would hide real failures
+			// indefinitely, so the endpoint only sets ones that expire.
+			http.Error(w, "expires is required", http.StatusBadRequest)
+			return
+		default:
+			if err := hc.setOverride(or.Check, or.Health, or.Reason, or.Expires); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
 func newReadyHandler(hc *Status) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if hc.ready == nil {
+		if !hc.hasReadiness() {
 			http.NotFound(w, r)
 			return
 		}
 
-		if hc.ready() {
+		rr := hc.CheckReady(r.Context())
+		if wantsVerbose(r) {
+			w.Header().Add("Content-Type", "application/json")
+			if !rr.Ready {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			if err := newEncoder(w).Encode(rr); err != nil {
+				log.Println("failed to write ready response")
+			}
+			return
+		}
+
+		switch {
+		case rr.Ready:
 			w.Header().Add("Content-Type", "text/plain")
 			w.WriteHeader(http.StatusOK)
 			fmt.Fprintf(w, "ready\n")
+		case rr.ShuttingDown:
+			w.Header().Add("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "shutting down\n")
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+}
+
+func newLiveHandler(hc *Status) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lr := hc.CheckLive(r.Context())
+		if wantsVerbose(r) {
+			w.Header().Add("Content-Type", "application/json")
+			if !lr.Live {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			if err := newEncoder(w).Encode(lr); err != nil {
+				log.Println("failed to write live response")
+			}
+			return
+		}
+
+		if lr.Live {
+			w.Header().Add("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, "live\n")
 		} else {
 			w.WriteHeader(http.StatusServiceUnavailable)
 		}
 	})
 }
 
-func newAboutHandler(os *Status) http.Handler {
-	j, err := json.MarshalIndent(os.About(), "  ", "  ")
-	if err != nil {
-		panic(err)
+func newStartupHandler(hc *Status) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hc.startupMu.Lock()
+		gates := len(hc.startupGates)
+		hc.startupMu.Unlock()
+		if gates == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		sr := hc.Startup()
+		w.Header().Add("Content-Type", "application/json")
+		if !sr.Started {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := newEncoder(w).Encode(sr); err != nil {
+			log.Println("failed to write startup response")
+		}
+	})
+}
+
+// wantsVerbose reports whether the request asked for a detailed JSON response,
+// either with a "verbose" query parameter or by accepting JSON.
+func wantsVerbose(r *http.Request) bool {
+	if _, ok := r.URL.Query()["verbose"]; ok {
+		return true
 	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
 
+func newAboutHandler(os *Status) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The about response is built on every request, as the instance
+		// info includes the uptime.
+		j, err := json.MarshalIndent(os.About(), "  ", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
 		w.Header().Add("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write(j)
+		_, err = w.Write(j)
 		if err != nil {
 			log.Println("failed to write about response")
 		}
 	})
 }
 
-// NewHandler created a new HTTP handler that should be mapped to "/__/".
+// Overload default mux in order to remove the handlers pprof binds to it. This
+// runs once, after the init of net/http/pprof and before the application gets
+// to register its own handlers.
+func init() {
+	http.DefaultServeMux = http.NewServeMux()
+}
+
+// Endpoints served by NewHandler, named by their path below the prefix. They
+// can be turned off with HandlerDisable.
+const (
+	EndpointAbout          = "about"
+	EndpointHealth         = "health"
+	EndpointHealthHistory  = "health/history"
+	EndpointHealthOverride = "health/override"
+	EndpointReady          = "ready"
+	EndpointStartup        = "startup"
+	EndpointLive           = "live"
+	EndpointMetrics        = "metrics"
+	EndpointPprof          = "extended/pprof/"
+)
+
+// HandlerOption configures the handler returned by NewHandler.
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	prefix   string
+	disabled map[string]bool
+	custom   map[string]http.Handler
+}
+
+// HandlerPrefix mounts the endpoints under prefix instead of "/__/", for
+// example "/ops/".
+func HandlerPrefix(prefix string) HandlerOption {
+	return func(c *handlerConfig) {
+		c.prefix = "/" + strings.Trim(prefix, "/") + "/"
+		if c.prefix == "//" {
+			c.prefix = "/"
+		}
+	}
+}
+
+// HandlerDisable turns off the given endpoints, for example EndpointMetrics
+// in services that already serve their own metrics.
+func HandlerDisable(endpoints ...string) HandlerOption {
+	return func(c *handlerConfig) {
+		for _, e := range endpoints {
+			c.disabled[e] = true
+		}
+	}
+}
+
+// HandlerEndpoint serves h at path below the prefix alongside the standard
+// endpoints. It replaces a standard endpoint with the same path.
+func HandlerEndpoint(path string, h http.Handler) HandlerOption {
+	return func(c *handlerConfig) {
+		c.custom[strings.TrimPrefix(path, "/")] = h
+	}
+}
+
+// NewHandler created a new HTTP handler that should be mapped to "/__/", or
+// to the prefix set with HandlerPrefix.
 // It will create all the standard endpoints it can based on how the OpStatus
 // is configured.
-func NewHandler(os *Status) http.Handler {
+func NewHandler(os *Status, opts ...HandlerOption) http.Handler {
+	c := &handlerConfig{
+		prefix:   "/__/",
+		disabled: map[string]bool{},
+		custom:   map[string]http.Handler{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	m := http.NewServeMux()
-	m.Handle("/__/about", newAboutHandler(os))
-	m.Handle("/__/health", newHealthCheckHandler(os))
-	m.Handle("/__/ready", newReadyHandler(os))
-	m.Handle("/__/metrics", promhttp.Handler())
+	handle := func(endpoint string, h http.Handler) {
+		if c.disabled[endpoint] || c.custom[endpoint] != nil {
+			return
+		}
+		m.Handle(c.prefix+endpoint, h)
+	}
 
-	// Overload default mux in order to stop pprof binding handlers to it
-	http.DefaultServeMux = http.NewServeMux()
+	handle(EndpointAbout, newAboutHandler(os))
+	handle(EndpointHealth, newHealthCheckHandler(os))
+	handle(EndpointHealthHistory, newHistoryHandler(os))
+	handle(EndpointHealthOverride, newOverrideHandler(os))
+	handle(EndpointReady, newReadyHandler(os))
+	handle(EndpointStartup, newStartupHandler(os))
+	handle(EndpointLive, newLiveHandler(os))
+	registerer, gatherer := os.Registry()
+	handle(EndpointMetrics, promhttp.InstrumentMetricHandler(registerer, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
 
 	// Register PPROF handlers
-	m.Handle("/__/extended/pprof/", http.HandlerFunc(pprof.Index))
-	m.Handle("/__/extended/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
-	m.Handle("/__/extended/pprof/profile", http.HandlerFunc(pprof.Profile))
-	m.Handle("/__/extended/pprof/symbol", http.HandlerFunc(pprof.Symbol))
-	m.Handle("/__/extended/pprof/trace", http.HandlerFunc(pprof.Trace))
-	m.Handle("/__/extended/pprof/goroutine", pprof.Handler("goroutine"))
-	m.Handle("/__/extended/pprof/heap", pprof.Handler("heap"))
-	m.Handle("/__/extended/pprof/threadcreate", pprof.Handler("threadcreate"))
-	m.Handle("/__/extended/pprof/block", pprof.Handler("block"))
-	m.Handle("/__/extended/pprof/mutex", pprof.Handler("mutex"))
-	m.Handle("/__/extended/pprof/allocs", pprof.Handler("allocs"))
+	if !c.disabled[EndpointPprof] {
+		handle(EndpointPprof, http.HandlerFunc(pprof.Index))
+		handle(EndpointPprof+"cmdline", http.HandlerFunc(pprof.Cmdline))
+		handle(EndpointPprof+"profile", http.HandlerFunc(pprof.Profile))
+		handle(EndpointPprof+"symbol", http.HandlerFunc(pprof.Symbol))
+		handle(EndpointPprof+"trace", http.HandlerFunc(pprof.Trace))
+		handle(EndpointPprof+"goroutine", pprof.Handler("goroutine"))
+		handle(EndpointPprof+"heap", pprof.Handler("heap"))
+		handle(EndpointPprof+"threadcreate", pprof.Handler("threadcreate"))
+		handle(EndpointPprof+"block", pprof.Handler("block"))
+		handle(EndpointPprof+"mutex", pprof.Handler("mutex"))
+		handle(EndpointPprof+"allocs", pprof.Handler("allocs"))
+	}
+
+	for path, h := range c.custom {
+		m.Handle(c.prefix+path, h)
+	}
 
 	return m
 }
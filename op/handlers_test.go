@@ -1,6 +1,8 @@
 package op
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -60,6 +62,62 @@ func TestAboutHandler(t *testing.T) {
 	assert.Equal(expectedAbout, rr.Body.String())
 }
 
+func TestAboutHandlerOwnerAndLinkDetails(t *testing.T) {
+	expectedAbout := `{
+    "name": "name",
+    "description": "desc",
+    "owners": [
+      {
+        "name": "team x",
+        "slack": "#team-x",
+        "email": "team-x@example.com",
+        "pagerduty": "PABC123",
+        "role": "primary"
+      },
+      {
+        "name": "team y"
+      }
+    ],
+    "links": [
+      {
+        "description": "how to fix things",
+        "url": "http://runbook/",
+        "type": "runbook"
+      },
+      {
+        "description": "plain link",
+        "url": "http://plain/"
+      }
+    ],
+    "build-info": {
+      "revision": ""
+    }
+  }`
+
+	h := newAboutHandler(
+		NewStatus("name", "desc").
+			AddOwner("team x", "#team-x",
+				OwnerEmail("team-x@example.com"),
+				OwnerPagerDuty("PABC123"),
+				OwnerRole(OwnerRolePrimary)).
+			AddOwner("team y", "").
+			AddLink("how to fix things", "http://runbook/", LinkType(LinkTypeRunbook)).
+			AddLink("plain link", "http://plain/"),
+	)
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, expectedAbout, rr.Body.String())
+}
+
 var expectedHealth = `{
     "name": "name",
     "description": "desc",
@@ -119,6 +177,55 @@ func TestHealthCheckHandler(t *testing.T) {
 	assert.Equal(expectedHealth, rr.Body.String())
 }
 
+func TestHealthCheckHandlerMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	h := newHealthCheckHandler(
+		NewStatus("name", "desc").
+			AddChecker("check1", func(cr *CheckResponse) {
+				cr.Unhealthy("output1", "action1", "impact1")
+				cr.SetRunbook("http://runbook/")
+				cr.SetDashboard("http://dashboard/")
+				cr.SetDependency("postgres")
+				cr.SetErrorCode("DB_UNREACHABLE")
+				cr.SetLabel("region", "eu-west-1")
+			}),
+	)
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(`{
+    "name": "name",
+    "description": "desc",
+    "health": "unhealthy",
+    "checks": [
+      {
+        "name": "check1",
+        "health": "unhealthy",
+        "output": "output1",
+        "action": "action1",
+        "impact": "impact1",
+        "runbook": "http://runbook/",
+        "dashboard": "http://dashboard/",
+        "dependency": "postgres",
+        "error-code": "DB_UNREACHABLE",
+        "labels": {
+          "region": "eu-west-1"
+        }
+      }
+    ]
+  }
+`, rr.Body.String())
+}
+
 func TestHealthCheckHandler_PostServeAddChecker(t *testing.T) {
 	assert := assert.New(t)
 
@@ -188,6 +295,48 @@ func TestReadyHandlerNotReady(t *testing.T) {
 	assert.Empty(rr.Body.String())
 }
 
+func TestReadyHandlerVerbose(t *testing.T) {
+	assert := assert.New(t)
+
+	h := newReadyHandler(NewStatus("", "").
+		ReadyAlways().
+		AddReadyChecker("migrations", func(ctx context.Context, cr *CheckResponse) {
+			cr.Unhealthy("migrations pending", "wait for migrations", "")
+		}))
+
+	req, err := http.NewRequest("GET", "/?verbose", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+	assert.Equal("application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(`{
+    "ready": false,
+    "checks": [
+      {
+        "name": "migrations",
+        "health": "unhealthy",
+        "output": "migrations pending",
+        "action": "wait for migrations"
+      }
+    ]
+  }
+`, rr.Body.String())
+
+	req.URL.RawQuery = ""
+	rr = httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+	assert.Empty(rr.Body.String())
+}
+
 func TestReadyHandlerNone(t *testing.T) {
 	h := newReadyHandler(NewStatus("", "").ReadyNone())
 
@@ -242,6 +391,96 @@ func TestReadyHandlerDefaults(t *testing.T) {
 	}
 }
 
+func TestStartupHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStatus("", "")
+	h := newStartupHandler(st)
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusNotFound, rr.Code, "Expected 404 when no startup gates are registered")
+
+	st.AddStartupGate("schema migrated").AddStartupGate("initial sync done")
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(`{
+    "started": false,
+    "pending": [
+      "schema migrated",
+      "initial sync done"
+    ]
+  }
+`, rr.Body.String())
+
+	st.CompleteStartupGate("schema migrated")
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(StartupResult{Pending: []string{"initial sync done"}}, st.Startup())
+
+	st.CompleteStartupGate("initial sync done")
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal(`{
+    "started": true
+  }
+`, rr.Body.String())
+}
+
+func TestLiveHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	var healthChecked bool
+	st := NewStatus("", "").
+		AddChecker("database", func(cr *CheckResponse) {
+			healthChecked = true
+			cr.Unhealthy("database is down", "fix the database", "everything")
+		})
+	h := newLiveHandler(st)
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("live\n", rr.Body.String())
+	assert.False(healthChecked, "live endpoint should not run health checkers")
+
+	st.AddLiveChecker("goroutines", GoroutineChecker(0))
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+
+	req.URL.RawQuery = "verbose"
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(rr.Body.String(), `"live": false`)
+	assert.Contains(rr.Body.String(), `goroutines running, more than the limit of 0`)
+
+	st.RemoveLiveCheckers("goroutines").AddLiveChecker("goroutines", GoroutineChecker(1e6))
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.False(healthChecked, "live endpoint should not run health checkers")
+}
+
 func TestMetricsHandler(t *testing.T) {
 	assert := assert.New(t)
 	metric := prometheus.NewCounter(prometheus.CounterOpts{
@@ -266,3 +505,97 @@ func TestMetricsHandler(t *testing.T) {
 	assert.Equal(http.StatusOK, rr.Code, "Response status should be 200")
 	assert.True(strings.Contains(rr.Body.String(), "test_metric 1\n"), "Metrics response should contain dummy metric")
 }
+
+func TestMetricsHandlerPrivateRegistry(t *testing.T) {
+	assert := assert.New(t)
+	metric := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "private_test_metric",
+		Help: "Dummy counter",
+	})
+
+	reg := prometheus.NewRegistry()
+	s := NewStatus("", "").WithRegistry(reg, reg).AddMetrics(metric).AddMetrics(metric)
+	h := NewHandler(s)
+
+	metric.Inc()
+
+	req, err := http.NewRequest("GET", "/__/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code, "Response status should be 200")
+	assert.Contains(rr.Body.String(), "private_test_metric 1\n", "Metrics response should contain dummy metric")
+	assert.NotContains(rr.Body.String(), "go_goroutines", "Metrics response should not contain default registry metrics")
+
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		assert.NotEqual("private_test_metric", mf.GetName(), "Metric should not be registered globally")
+	}
+}
+
+func TestHandlerOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewStatus("my app", "app description").ReadyAlways()
+	h := NewHandler(s,
+		HandlerPrefix("ops"),
+		HandlerDisable(EndpointMetrics, EndpointPprof),
+		HandlerEndpoint("/version", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "v1.2.3")
+		})),
+		HandlerEndpoint(EndpointLive, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "custom live")
+		})),
+	)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	assert.Equal(http.StatusOK, get("/ops/ready").Code)
+	assert.Equal(http.StatusOK, get("/ops/about").Code)
+	assert.Equal(http.StatusNotFound, get("/__/ready").Code)
+	assert.Equal(http.StatusNotFound, get("/ops/metrics").Code)
+	assert.Equal(http.StatusNotFound, get("/ops/extended/pprof/heap").Code)
+	assert.Equal("v1.2.3", get("/ops/version").Body.String())
+	assert.Equal("custom live", get("/ops/live").Body.String())
+}
+
+func TestHandlerDisablePprofKeepsDefaultServeMux(t *testing.T) {
+	assert := assert.New(t)
+
+	defaultMux := http.DefaultServeMux
+	defer func() { http.DefaultServeMux = defaultMux }()
+
+	http.DefaultServeMux = http.NewServeMux()
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "app metrics")
+	})
+
+	NewHandler(NewStatus("my app", "app description"), HandlerDisable(EndpointPprof, EndpointMetrics))
+
+	rr := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal("app metrics", rr.Body.String())
+}
+
+func TestHandlerDisablePprofDefaultServeMux(t *testing.T) {
+	assert := assert.New(t)
+
+	NewHandler(NewStatus("my app", "app description"), HandlerDisable(EndpointPprof))
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline"} {
+		rr := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(http.StatusNotFound, rr.Code, "pprof should not be served on the default mux at %s", path)
+	}
+}
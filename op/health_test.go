@@ -1,7 +1,9 @@
 package op
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -101,3 +103,154 @@ func TestReadyUseHealthCheck(t *testing.T) {
 		}).ReadyUseHealthCheck().ready()
 	assert.False(unhealthyReady)
 }
+
+func TestHealthCheckContextTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").
+		WithCheckTimeout(10*time.Millisecond).
+		AddCheckerContext("hangs", func(ctx context.Context, cr *CheckResponse) {
+			<-ctx.Done()
+		}).
+		AddChecker("ignores context", func(cr *CheckResponse) {
+			time.Sleep(time.Second)
+		}, CheckerTimeout(20*time.Millisecond)).
+		AddCheckerContext("fast", func(ctx context.Context, cr *CheckResponse) {
+			cr.Healthy("done")
+		})
+
+	start := time.Now()
+	result := hc.Check()
+	assert.Less(time.Since(start), time.Second)
+
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal(healthResultEntry{Name: "hangs", Health: "unhealthy", Output: "check timed out after 10ms"}, result.CheckResults[0])
+	assert.Equal(healthResultEntry{Name: "ignores context", Health: "unhealthy", Output: "check timed out after 20ms"}, result.CheckResults[1])
+	assert.Equal(healthResultEntry{Name: "fast", Health: "healthy", Output: "done"}, result.CheckResults[2])
+}
+
+func TestHealthCheckContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").
+		AddCheckerContext("hangs", func(ctx context.Context, cr *CheckResponse) {
+			<-ctx.Done()
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := hc.CheckContext(ctx)
+
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("check cancelled: context canceled", result.CheckResults[0].Output)
+}
+
+func TestHealthCheckUnreported(t *testing.T) {
+	assert := assert.New(t)
+
+	result := NewStatus("my app", "app description").
+		AddChecker("forgetful", func(cr *CheckResponse) {}).
+		AddChecker("fine", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		}).
+		Check()
+
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal(healthResultEntry{
+		Name:   "forgetful",
+		Health: "unhealthy",
+		Output: "check did not report a result",
+		Action: "fix the check so that it calls Healthy, Degraded or Unhealthy",
+		Impact: "the health of this dependency is not known",
+	}, result.CheckResults[0])
+
+	result = NewStatus("my app", "app description").
+		WithUnknownChecks().
+		AddChecker("forgetful", func(cr *CheckResponse) {}).
+		Check()
+
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("unknown", result.CheckResults[0].Health)
+	assert.Equal("check did not report a result", result.CheckResults[0].Output)
+}
+
+func TestHealthCheckNonCritical(t *testing.T) {
+	assert := assert.New(t)
+
+	result := NewStatus("my app", "app description").
+		AddChecker("database", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		}).
+		AddChecker("recommendations", func(cr *CheckResponse) {
+			cr.Unhealthy("connection refused", "restart recommendations", "no recommendations shown")
+		}, NonCritical()).
+		Check()
+
+	assert.Equal("degraded", result.Health)
+	assert.Equal("unhealthy", result.CheckResults[1].Health)
+
+	result = NewStatus("my app", "app description").
+		AddChecker("database", func(cr *CheckResponse) {
+			cr.Unhealthy("connection refused", "restart database", "nothing works")
+		}).
+		AddChecker("recommendations", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		}, NonCritical()).
+		Check()
+
+	assert.Equal("unhealthy", result.Health)
+}
+
+func TestCheckReady(t *testing.T) {
+	assert := assert.New(t)
+
+	var cacheLoaded bool
+	hc := NewStatus("my app", "app description").
+		AddChecker("health only", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		}).
+		AddReadyChecker("cache loaded", func(ctx context.Context, cr *CheckResponse) {
+			if !cacheLoaded {
+				cr.Unhealthy("cache is still loading", "wait", "requests cannot be served")
+				return
+			}
+			cr.Healthy("cache loaded")
+		})
+
+	result := hc.CheckReady(context.Background())
+	assert.Equal(ReadyResult{
+		Ready: false,
+		CheckResults: []healthResultEntry{
+			{Name: "cache loaded", Health: "unhealthy", Output: "cache is still loading", Action: "wait", Impact: "requests cannot be served"},
+		},
+	}, result)
+
+	cacheLoaded = true
+	assert.True(hc.CheckReady(context.Background()).Ready)
+	assert.Len(hc.Check().CheckResults, 1, "ready checkers should not be part of the health check")
+
+	hc.ReadyNever()
+	assert.False(hc.CheckReady(context.Background()).Ready)
+
+	hc.ReadyNone().RemoveReadyCheckers("cache loaded")
+	assert.False(hc.CheckReady(context.Background()).Ready)
+}
+
+func TestHealthCheckTimings(t *testing.T) {
+	assert := assert.New(t)
+
+	check := func(cr *CheckResponse) {
+		time.Sleep(time.Millisecond)
+		cr.Healthy("ok")
+	}
+
+	result := NewStatus("my app", "app description").AddChecker("check", check).Check()
+	assert.Nil(result.CheckResults[0].LastRun)
+	assert.Zero(result.CheckResults[0].Duration)
+
+	before := time.Now()
+	result = NewStatus("my app", "app description").WithCheckTimings().AddChecker("check", check).Check()
+	assert.NotNil(result.CheckResults[0].LastRun)
+	assert.False(result.CheckResults[0].LastRun.Before(before))
+	assert.GreaterOrEqual(result.CheckResults[0].Duration, time.Millisecond.Seconds())
+}
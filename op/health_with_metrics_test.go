@@ -1,7 +1,13 @@
 package op
 
 import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 
 	"testing"
@@ -69,6 +75,118 @@ func TestHealthCheckWithMetrics(t *testing.T) {
 
 }
 
+func TestHealthCheckPanicWithMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").
+		AddChecker("check panics", func(cr *CheckResponse) {
+			panic("boom")
+		}).
+		AddChecker("check ok", func(cr *CheckResponse) {
+			cr.Healthy("fine")
+		})
+	hc.checkPanicCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: healthcheckPanics,
+	}, []string{healthcheckName})
+
+	result := hc.Check()
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("unhealthy", result.CheckResults[0].Health)
+	assert.True(strings.HasPrefix(result.CheckResults[0].Output, "check panicked: boom\n"), "output should contain the panic value")
+	assert.Contains(result.CheckResults[0].Output, "TestHealthCheckPanicWithMetrics", "output should contain the panicking frame")
+	assert.LessOrEqual(strings.Count(result.CheckResults[0].Output, "\n"), maxPanicStackLines)
+	assert.Equal("healthy", result.CheckResults[1].Health)
+
+	assert.Equal(float64(1), testutil.ToFloat64(hc.checkPanicCounter.WithLabelValues("check_panics")))
+}
+
+func TestHealthCheckUnknownWithMetrics(t *testing.T) {
+	hc := NewStatus("my app", "app description").
+		WithUnknownChecks().
+		AddChecker("check forgetful", func(cr *CheckResponse) {})
+	hc.checkResultGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: healthcheckStatus,
+	}, []string{healthcheckName, healthcheckResult})
+
+	hc.Check()
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(hc.checkResultGauge.WithLabelValues("check_forgetful", unknown)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(hc.checkResultGauge.WithLabelValues("check_forgetful", unhealthy)))
+}
+
+func TestHealthCheckDurationWithMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	var fail bool
+	hc := NewStatus("my app", "app description").
+		AddChecker("check db", func(cr *CheckResponse) {
+			if fail {
+				cr.Unhealthy("down", "fix it", "everything")
+				return
+			}
+			cr.Healthy("ok")
+		})
+	hc.checkDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: healthcheckDuration,
+	}, []string{healthcheckName})
+	hc.checkLastSuccessGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: healthcheckLastSuccess,
+	}, []string{healthcheckName})
+
+	before := float64(time.Now().Unix())
+	hc.Check()
+	lastSuccess := testutil.ToFloat64(hc.checkLastSuccessGauge.WithLabelValues("check_db"))
+	assert.GreaterOrEqual(lastSuccess, before)
+
+	fail = true
+	hc.Check()
+	assert.Equal(lastSuccess, testutil.ToFloat64(hc.checkLastSuccessGauge.WithLabelValues("check_db")), "failed check should not update last success")
+
+	assert.Equal(1, testutil.CollectAndCount(hc.checkDurationHistogram))
+	assert.Equal(uint64(2), histogramSampleCount(t, hc.checkDurationHistogram))
+}
+
+func TestInstrumentedChecksPrivateRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	check := func(cr *CheckResponse) {
+		cr.Healthy("ok")
+	}
+
+	reg1, reg2 := prometheus.NewRegistry(), prometheus.NewRegistry()
+	hc1 := NewStatus("app 1", "").WithRegistry(reg1, reg1).AddChecker("check one", check).WithInstrumentedChecks()
+	hc2 := NewStatus("app 2", "").WithRegistry(reg2, reg2).AddChecker("check two", check).WithInstrumentedChecks()
+	hc1.Check()
+	hc2.Check()
+
+	mfs, err := reg1.Gather()
+	assert.NoError(err)
+	assertMetricLabelsAndValue(t, mfs, "check_one", healthy, 1)
+
+	mfs, err = reg2.Gather()
+	assert.NoError(err)
+	assertMetricLabelsAndValue(t, mfs, "check_two", healthy, 1)
+
+	// Instrumenting the same registry again re-uses the existing metrics.
+	hc3 := NewStatus("app 3", "").WithRegistry(reg1, reg1).AddChecker("check three", check).WithInstrumentedChecks()
+	hc3.Check()
+
+	mfs, err = reg1.Gather()
+	assert.NoError(err)
+	assertMetricLabelsAndValue(t, mfs, "check_one", healthy, 1)
+	assertMetricLabelsAndValue(t, mfs, "check_three", healthy, 1)
+}
+
+func histogramSampleCount(t *testing.T, c prometheus.Collector) uint64 {
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mfs[0].Metric[0].GetHistogram().GetSampleCount()
+}
+
 func assertMetricLabelsAndValue(t *testing.T, mfs []*dto.MetricFamily, checkname string, outcome string, value int) {
 	for _, mf := range mfs {
 		if mf.GetName() == healthcheckStatus && mf.GetType() == dto.MetricType_GAUGE {
@@ -91,3 +209,31 @@ func assertMetricLabelsAndValue(t *testing.T, mfs []*dto.MetricFamily, checkname
 	}
 	assert.Fail(t, "Expected counter to match labels and count, but nt")
 }
+
+func histogramSampleSum(t *testing.T, c prometheus.Collector) float64 {
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mfs[0].Metric[0].GetHistogram().GetSampleSum()
+}
+
+func TestAddMetricsDuplicateCollectorLogged(t *testing.T) {
+	assert := assert.New(t)
+
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	opts := prometheus.CounterOpts{Name: "duplicate_test_metric", Help: "Dummy counter"}
+	first := prometheus.NewCounter(opts)
+	reg := prometheus.NewRegistry()
+	hc := NewStatus("", "").WithRegistry(reg, reg).AddMetrics(first).AddMetrics(first)
+	assert.Empty(logs.String(), "adding the same collector twice should not log")
+
+	hc.AddMetrics(prometheus.NewCounter(opts))
+	assert.Contains(logs.String(), "a different collector with the same metrics is already registered")
+}
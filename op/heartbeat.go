@@ -0,0 +1,64 @@
+package op
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat is a watchdog for long running worker loops. The worker calls Beat
+// on every iteration and the heartbeat's Check method, added with AddChecker,
+// reports the worker as stuck once the beats stop arriving.
+type Heartbeat struct {
+	interval  time.Duration
+	maxMissed int
+	last      int64 // unix nanoseconds of the last beat
+	now       func() time.Time
+}
+
+// NewHeartbeat returns a Heartbeat expecting a beat at least every interval.
+// It reports degraded after one missed interval and unhealthy after maxMissed
+// missed intervals, or after one if maxMissed is less than one. The time it
+// is created counts as the first beat. Like time.NewTicker, it panics if
+// interval is not positive.
+func NewHeartbeat(interval time.Duration, maxMissed int) *Heartbeat {
+	if interval <= 0 {
+		panic("op: non-positive interval for NewHeartbeat")
+	}
+	if maxMissed < 1 {
+		maxMissed = 1
+	}
+	h := &Heartbeat{interval: interval, maxMissed: maxMissed, now: time.Now}
+	h.Beat()
+	return h
+}
+
+// Beat records that the worker is still making progress. It is safe to call
+// concurrently.
+func (h *Heartbeat) Beat() {
+	atomic.StoreInt64(&h.last, h.now().UnixNano())
+}
+
+// Check is a checker, for use with AddChecker, that reports on how long ago
+// the last beat was.
+func (h *Heartbeat) Check(cr *CheckResponse) {
+	since := h.now().Sub(time.Unix(0, atomic.LoadInt64(&h.last))).Round(time.Millisecond)
+	missed := int(since / h.interval)
+	output := fmt.Sprintf("last beat %s ago", since)
+
+	switch {
+	case missed >= h.maxMissed:
+		cr.Unhealthy(output, "check whether the worker is stuck and restart it", "work is not being processed")
+	case missed >= 1:
+		cr.Degraded(output, "check whether the worker is stuck")
+	default:
+		cr.Healthy(output)
+	}
+}
+
+// CheckContext is the same as Check, for use with AddCheckerContext or
+// AddLiveChecker.
+func (h *Heartbeat) CheckContext(_ context.Context, cr *CheckResponse) {
+	h.Check(cr)
+}
@@ -0,0 +1,57 @@
+package op
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeat(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	hb := NewHeartbeat(time.Second, 3)
+	hb.now = func() time.Time { return now }
+	hb.Beat()
+
+	hc := NewStatus("my app", "app description").
+		AddChecker("worker", hb.Check).
+		AddLiveChecker("worker", hb.CheckContext)
+
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(healthResultEntry{Name: "worker", Health: "healthy", Output: "last beat 500ms ago"}, hc.Check().CheckResults[0])
+
+	now = now.Add(time.Second)
+	result := hc.Check().CheckResults[0]
+	assert.Equal("degraded", result.Health)
+	assert.Equal("last beat 1.5s ago", result.Output)
+
+	now = now.Add(2 * time.Second)
+	result = hc.Check().CheckResults[0]
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("last beat 3.5s ago", result.Output)
+	assert.False(hc.CheckLive(context.Background()).Live)
+
+	hb.Beat()
+	assert.Equal("healthy", hc.Check().CheckResults[0].Health)
+	assert.True(hc.CheckLive(context.Background()).Live)
+}
+
+func TestHeartbeatInvalidSettings(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Panics(func() { NewHeartbeat(0, 3) })
+	assert.Panics(func() { NewHeartbeat(-time.Second, 3) })
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	hb := NewHeartbeat(time.Second, 0)
+	hb.now = func() time.Time { return now }
+	hb.Beat()
+
+	now = now.Add(1500 * time.Millisecond)
+	var cr CheckResponse
+	hb.Check(&cr)
+	assert.Equal("unhealthy", cr.health, "maxMissed below one should be treated as one")
+}
@@ -0,0 +1,131 @@
+package op
+
+import (
+	"time"
+)
+
+// WithCheckHistory keeps the last size results of each checker, served on the
+// /__/health/history endpoint.
+func (s *Status) WithCheckHistory(size int) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.historySize = size
+	return s
+}
+
+// WithFlapDetection marks a check as flapping when its health has changed
+// more than maxChanges times within window. A flapping check that currently
+// reports healthy is reported as degraded instead, so that intermittent
+// failures are visible.
+func (s *Status) WithFlapDetection(maxChanges int, window time.Duration) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flapChanges = maxChanges
+	s.flapWindow = window
+	return s
+}
+
+// historyEnabled reports whether check history is being kept.
+func (s *Status) historyEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.historySize > 0
+}
+
+// recordHistory adds the result of a checker run to its history, and reports
+// whether the checker is flapping.
+func (s *Status) recordHistory(ch *checker, e healthResultEntry, start time.Time, duration time.Duration) bool {
+	s.mu.RLock()
+	historySize, flapChanges, flapWindow := s.historySize, s.flapChanges, s.flapWindow
+	s.mu.RUnlock()
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if historySize > 0 {
+		he := HistoryEntry{
+			Time:     start,
+			Health:   e.Health,
+			Output:   e.Output,
+			Duration: duration.Seconds(),
+		}
+		if len(ch.history) < historySize {
+			ch.history = append(ch.history, he)
+		} else {
+			ch.history[ch.historyNext%len(ch.history)] = he
+		}
+		ch.historyNext = (ch.historyNext + 1) % historySize
+	}
+
+	if flapWindow <= 0 {
+		return false
+	}
+	if ch.lastHealth != "" && ch.lastHealth != e.Health {
+		ch.transitions = append(ch.transitions, start)
+	}
+	ch.lastHealth = e.Health
+
+	cutoff := start.Add(-flapWindow)
+	for len(ch.transitions) > 0 && ch.transitions[0].Before(cutoff) {
+		ch.transitions = ch.transitions[1:]
+	}
+	return len(ch.transitions) > flapChanges
+}
+
+// markFlapping marks an entry as flapping. A healthy entry is degraded so
+// that the intermittent failures are visible.
+func markFlapping(e *healthResultEntry) {
+	e.Flapping = true
+	if e.Health == healthy {
+		e.Health = degraded
+		e.Action = "investigate the intermittent failures of this check"
+	}
+}
+
+// History returns the recent results of each checker, oldest first.
+func (s *Status) History() HistoryResult {
+	s.mu.RLock()
+	checkers := s.checkers
+	s.mu.RUnlock()
+
+	hr := HistoryResult{
+		Name:        s.name,
+		Description: s.description,
+		Checks:      make([]checkHistory, len(checkers)),
+	}
+
+	for i, ch := range checkers {
+		ch.mu.Lock()
+		history := make([]HistoryEntry, 0, len(ch.history))
+		// Once the buffer is full the oldest entry is the next to be replaced.
+		history = append(history, ch.history[ch.historyNext:]...)
+		history = append(history, ch.history[:ch.historyNext]...)
+		ch.mu.Unlock()
+
+		hr.Checks[i] = checkHistory{Name: ch.name, History: history}
+	}
+	return hr
+}
+
+// HistoryResult represents the recent results of each of an application's
+// checkers.
+type HistoryResult struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Checks      []checkHistory `json:"checks"`
+}
+
+type checkHistory struct {
+	Name    string         `json:"name"`
+	History []HistoryEntry `json:"history"`
+}
+
+// HistoryEntry is a single past result of a checker.
+type HistoryEntry struct {
+	Time     time.Time `json:"time"`
+	Health   string    `json:"health"`
+	Output   string    `json:"output"`
+	Duration float64   `json:"duration-seconds"`
+}
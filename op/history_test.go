@@ -0,0 +1,130 @@
+package op
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	var run int
+	hc := NewStatus("my app", "app description").
+		WithCheckHistory(3).
+		AddChecker("counter", func(cr *CheckResponse) {
+			run++
+			cr.Healthy(fmt.Sprintf("run %d", run))
+		})
+
+	assert.Empty(hc.History().Checks[0].History)
+
+	for i := 0; i < 5; i++ {
+		hc.Check()
+	}
+
+	history := hc.History().Checks[0].History
+	assert.Len(history, 3)
+	for i, he := range history {
+		assert.Equal(fmt.Sprintf("run %d", i+3), he.Output)
+		assert.Equal("healthy", he.Health)
+		assert.False(he.Time.IsZero())
+	}
+	assert.True(history[0].Time.Before(history[2].Time))
+}
+
+func TestHistoryHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStatus("my app", "app description").
+		AddChecker("check", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		})
+	h := newHistoryHandler(st)
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusNotFound, rr.Code, "Expected 404 when history is not enabled")
+
+	st.WithCheckHistory(10).Check()
+
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Contains(rr.Body.String(), `"output": "ok"`)
+	assert.Contains(rr.Body.String(), `"duration-seconds"`)
+}
+
+func TestFlapDetection(t *testing.T) {
+	assert := assert.New(t)
+
+	var fail bool
+	hc := NewStatus("my app", "app description").
+		WithFlapDetection(2, time.Minute).
+		AddChecker("flaky", func(cr *CheckResponse) {
+			if fail {
+				cr.Unhealthy("failed", "retry", "some requests fail")
+			} else {
+				cr.Healthy("ok")
+			}
+		})
+
+	assert.Equal("healthy", hc.Check().Health)
+	fail = true
+	assert.False(hc.Check().CheckResults[0].Flapping)
+	fail = false
+	assert.Equal("healthy", hc.Check().Health)
+	fail = true
+	result := hc.Check()
+	assert.True(result.CheckResults[0].Flapping)
+	assert.Equal("unhealthy", result.CheckResults[0].Health)
+
+	fail = false
+	result = hc.Check()
+	assert.Equal("degraded", result.Health)
+	assert.Equal(healthResultEntry{
+		Name:     "flaky",
+		Health:   "degraded",
+		Output:   "ok",
+		Action:   "investigate the intermittent failures of this check",
+		Flapping: true,
+	}, result.CheckResults[0])
+}
+
+func TestHistoryIgnoresCancelledRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	hc := NewStatus("my app", "app description").
+		WithCheckHistory(3).
+		WithFlapDetection(1, time.Minute).
+		AddCheckerContext("db", func(ctx context.Context, cr *CheckResponse) {
+			if ctx.Err() != nil {
+				// Outlive the caller, as a check ignoring its context would.
+				<-release
+			}
+			cr.Healthy("ok")
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hc.CheckContext(ctx)
+	hc.CheckContext(ctx)
+	assert.Empty(hc.History().Checks[0].History)
+
+	result := hc.Check()
+	assert.Equal("healthy", result.Health)
+	assert.False(result.CheckResults[0].Flapping)
+	assert.Len(hc.History().Checks[0].History, 1)
+}
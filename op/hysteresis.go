@@ -0,0 +1,66 @@
+package op
+
+// FailureThreshold makes the checker only report unhealthy after n
+// consecutive unhealthy results, so that a single failure doesn't flip the
+// health of the application. Until then the previous result is reported.
+func FailureThreshold(n int) CheckerOption {
+	return func(ch *checker) {
+		ch.failureThreshold = n
+	}
+}
+
+// SuccessThreshold makes an unhealthy checker only report healthy, or
+// degraded, again after n consecutive such results. Until then the previous
+// unhealthy result is reported.
+func SuccessThreshold(n int) CheckerOption {
+	return func(ch *checker) {
+		ch.successThreshold = n
+	}
+}
+
+// smooth applies the failure and success thresholds of the checker to a raw
+// result. The raw result is kept on the returned entry.
+func (ch *checker) smooth(e healthResultEntry) healthResultEntry {
+	if ch.failureThreshold <= 1 && ch.successThreshold <= 1 {
+		return e
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	raw := &rawResult{Health: e.Health, Output: e.Output, Action: e.Action, Impact: e.Impact}
+	failed := e.Health != healthy && e.Health != degraded
+	if failed {
+		ch.failures++
+		ch.successes = 0
+	} else {
+		ch.successes++
+		ch.failures = 0
+	}
+
+	if ch.smoothed != nil {
+		smoothedFailed := ch.smoothed.Health != healthy && ch.smoothed.Health != degraded
+		switch {
+		case failed && !smoothedFailed && ch.failures < ch.failureThreshold,
+			!failed && smoothedFailed && ch.successes < ch.successThreshold:
+			// Not enough consecutive results yet to change state.
+			held := *ch.smoothed
+			held.Raw = raw
+			return held
+		}
+	}
+
+	smoothed := e
+	ch.smoothed = &smoothed
+	e.Raw = raw
+	return e
+}
+
+// rawResult is the actual last result of a checker with failure or success
+// thresholds, which may differ from the one reported.
+type rawResult struct {
+	Health string `json:"health"`
+	Output string `json:"output"`
+	Action string `json:"action,omitempty"`
+	Impact string `json:"impact,omitempty"`
+}
@@ -0,0 +1,90 @@
+package op
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHysteresis(t *testing.T) {
+	assert := assert.New(t)
+
+	var fail bool
+	hc := NewStatus("my app", "app description").
+		AddChecker("ping", func(cr *CheckResponse) {
+			if fail {
+				cr.Unhealthy("ping failed", "check the network", "requests fail")
+			} else {
+				cr.Healthy("ping ok")
+			}
+		}, FailureThreshold(3), SuccessThreshold(2))
+
+	result := hc.Check()
+	assert.Equal("healthy", result.Health)
+	assert.Equal(&rawResult{Health: "healthy", Output: "ping ok"}, result.CheckResults[0].Raw)
+
+	fail = true
+	for i := 0; i < 2; i++ {
+		result = hc.Check()
+		assert.Equal("healthy", result.Health, "should stay healthy until the failure threshold is reached")
+		assert.Equal("ping ok", result.CheckResults[0].Output)
+		assert.Equal(&rawResult{Health: "unhealthy", Output: "ping failed", Action: "check the network", Impact: "requests fail"}, result.CheckResults[0].Raw)
+	}
+
+	result = hc.Check()
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("ping failed", result.CheckResults[0].Output)
+
+	fail = false
+	result = hc.Check()
+	assert.Equal("unhealthy", result.Health, "should stay unhealthy until the success threshold is reached")
+	assert.Equal("healthy", result.CheckResults[0].Raw.Health)
+
+	fail = true
+	assert.Equal("unhealthy", hc.Check().Health)
+	fail = false
+	assert.Equal("unhealthy", hc.Check().Health, "successes should be consecutive")
+	assert.Equal("healthy", hc.Check().Health)
+}
+
+func TestHysteresisNotConfigured(t *testing.T) {
+	result := NewStatus("my app", "app description").
+		AddChecker("ping", func(cr *CheckResponse) {
+			cr.Unhealthy("ping failed", "check the network", "requests fail")
+		}).
+		Check()
+
+	assert.Equal(t, "unhealthy", result.Health)
+	assert.Nil(t, result.CheckResults[0].Raw)
+}
+
+func TestHysteresisIgnoresCancelledRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	hc := NewStatus("my app", "app description").
+		AddCheckerContext("db", func(ctx context.Context, cr *CheckResponse) {
+			if ctx.Err() != nil {
+				<-release
+			}
+			cr.Healthy("ok")
+		}, FailureThreshold(2))
+
+	assert.Equal("healthy", hc.Check().Health)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		hc.CheckContext(ctx)
+	}
+
+	hc.mu.RLock()
+	ch := hc.checkers[0]
+	hc.mu.RUnlock()
+	ch.mu.Lock()
+	assert.Zero(ch.failures, "cancelled runs should not count as failures")
+	ch.mu.Unlock()
+	assert.Equal("healthy", hc.Check().Health)
+}
@@ -0,0 +1,74 @@
+package op
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// serviceAccountNamespaceFile is where Kubernetes mounts the namespace of the
+// pod, used when POD_NAMESPACE isn't set.
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// WithInstanceInfo adds details identifying this particular instance of the
+// application to About: when it started, its uptime and its hostname. When
+// running in Kubernetes, the pod, namespace and node names are read from the
+// POD_NAME, POD_NAMESPACE and NODE_NAME environment variables, typically set
+// using the downward API, with the namespace falling back to the one mounted
+// with the service account.
+func (s *Status) WithInstanceInfo() *Status {
+	info := instanceInfo{
+		pod:       os.Getenv("POD_NAME"),
+		namespace: os.Getenv("POD_NAMESPACE"),
+		node:      os.Getenv("NODE_NAME"),
+	}
+	info.hostname, _ = os.Hostname()
+	if info.namespace == "" {
+		if b, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+			info.namespace = strings.TrimSpace(string(b))
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instanceInfo = &info
+	return s
+}
+
+// StartTime returns the time the status was created, which is usually when
+// the application started.
+func (s *Status) StartTime() time.Time {
+	return s.startTime
+}
+
+func (s *Status) instanceResponse() *instanceResponse {
+	if s.instanceInfo == nil {
+		return nil
+	}
+	ir := &instanceResponse{
+		Hostname:  s.instanceInfo.hostname,
+		Pod:       s.instanceInfo.pod,
+		Namespace: s.instanceInfo.namespace,
+		Node:      s.instanceInfo.node,
+	}
+	if !s.startTime.IsZero() {
+		ir.StartTime = s.startTime.UTC().Format(time.RFC3339)
+		ir.Uptime = time.Since(s.startTime).Round(time.Second).String()
+	}
+	return ir
+}
+
+type instanceInfo struct {
+	hostname  string
+	pod       string
+	namespace string
+	node      string
+}
+
+type instanceResponse struct {
+	StartTime string `json:"start-time,omitempty"`
+	Uptime    string `json:"uptime,omitempty"`
+	Hostname  string `json:"hostname,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Node      string `json:"node,omitempty"`
+}
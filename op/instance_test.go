@@ -0,0 +1,43 @@
+package op
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithInstanceInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(NewStatus("my app", "app description").About().Instance)
+
+	t.Setenv("POD_NAME", "my-app-7d4b9c-x2k8p")
+	t.Setenv("POD_NAMESPACE", "")
+	t.Setenv("NODE_NAME", "node-1")
+
+	nsFile := filepath.Join(t.TempDir(), "namespace")
+	if err := os.WriteFile(nsFile, []byte("my-namespace\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(f string) { serviceAccountNamespaceFile = f }(serviceAccountNamespaceFile)
+	serviceAccountNamespaceFile = nsFile
+
+	hc := NewStatus("my app", "app description").WithInstanceInfo()
+	hc.startTime = time.Now().Add(-90 * time.Second)
+	hostname, _ := os.Hostname()
+
+	assert.Equal(&instanceResponse{
+		StartTime: hc.startTime.UTC().Format(time.RFC3339),
+		Uptime:    "1m30s",
+		Hostname:  hostname,
+		Pod:       "my-app-7d4b9c-x2k8p",
+		Namespace: "my-namespace",
+		Node:      "node-1",
+	}, hc.About().Instance)
+
+	t.Setenv("POD_NAMESPACE", "from-env")
+	assert.Equal("from-env", hc.WithInstanceInfo().About().Instance.Namespace)
+}
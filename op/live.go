@@ -0,0 +1,80 @@
+package op
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// AddLiveChecker adds a function that checks whether the application is still
+// alive, such as a watchdog on a worker loop. Live checkers are run by the
+// /__/live endpoint, intended for use as a Kubernetes liveness probe, which
+// never runs the health checkers. They should be cheap and must not depend on
+// external systems, since failing them gets the application restarted.
+func (s *Status) AddLiveChecker(name string, checkerFunc func(ctx context.Context, cr *CheckResponse), opts ...CheckerOption) *Status {
+	ch := &checker{name: name, checkFunc: checkerFunc}
+	for _, opt := range opts {
+		opt(ch)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.liveCheckers = append(s.liveCheckers, ch)
+	return s
+}
+
+// RemoveLiveCheckers will remove live check functions added by
+// AddLiveChecker. If multiple checks have been added with the same name,
+// these will all be removed.
+func (s *Status) RemoveLiveCheckers(name string) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var checkers []*checker
+	for _, ch := range s.liveCheckers {
+		if ch.name != name {
+			checkers = append(checkers, ch)
+		}
+	}
+	s.liveCheckers = checkers
+	return s
+}
+
+// CheckLive returns the current liveness of the application, along with the
+// result of each live checker. An application without live checkers is
+// always live.
+func (s *Status) CheckLive(ctx context.Context) LiveResult {
+	s.mu.RLock()
+	checkers := s.liveCheckers
+	s.mu.RUnlock()
+
+	lr := LiveResult{
+		CheckResults: s.runCheckersUnrecorded(ctx, checkers),
+	}
+	lr.Live = passing(lr.CheckResults)
+	return lr
+}
+
+// GoroutineChecker returns a live checker that reports unhealthy once the
+// number of goroutines exceeds max, which usually means they are leaking.
+func GoroutineChecker(max int) func(ctx context.Context, cr *CheckResponse) {
+	return func(ctx context.Context, cr *CheckResponse) {
+		n := runtime.NumGoroutine()
+		if n > max {
+			cr.Unhealthy(
+				fmt.Sprintf("%d goroutines running, more than the limit of %d", n, max),
+				"check the goroutine profile for leaks",
+				"the application may run out of memory",
+			)
+			return
+		}
+		cr.Healthy(fmt.Sprintf("%d goroutines running", n))
+	}
+}
+
+// LiveResult represents the current liveness of an application, along with
+// the result of each of its live checkers.
+type LiveResult struct {
+	Live         bool                `json:"live"`
+	CheckResults []healthResultEntry `json:"checks"`
+}
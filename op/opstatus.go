@@ -1,9 +1,16 @@
 package op
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -12,33 +19,57 @@ const (
 	healthy           = "healthy"
 	degraded          = "degraded"
 	unhealthy         = "unhealthy"
+	unknown           = "unknown"
 	healthcheckName   = "healthcheck_name"
 	healthcheckResult = "healthcheck_result"
 	healthcheckStatus = "healthcheck_status"
+	healthcheckPanics = "healthcheck_panics_total"
+
+	healthcheckDuration    = "healthcheck_duration_seconds"
+	healthcheckLastSuccess = "healthcheck_last_success_timestamp_seconds"
+
+	// maxPanicStackLines limits how much of a panicking checker's stack trace
+	// is included in its output.
+	maxPanicStackLines = 20
 )
 
 // NewStatus returns a new Status, given an application or service name and
 // description.
 func NewStatus(name, description string) *Status {
-	return &Status{name: name, description: description, loggerEnabled: false}
+	return &Status{name: name, description: description, loggerEnabled: false, startTime: time.Now()}
 }
 
 // AddOwner adds an owner entry. Each can have a name, a slack channel or both.
-// Multiple owner entries are allowed.
-func (s *Status) AddOwner(name, slack string) *Status {
-	s.owners = append(s.owners, owner{name: name, slack: slack})
+// Multiple owner entries are allowed. Further contact details can be given
+// with options such as OwnerEmail.
+func (s *Status) AddOwner(name, slack string, opts ...OwnerOption) *Status {
+	o := owner{name: name, slack: slack}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners = append(s.owners, o)
 	return s
 }
 
 // AddLink adds a URL link. Multiple are allowed and each should have a brief
-// description.
-func (s *Status) AddLink(description, url string) *Status {
-	s.links = append(s.links, link{description: description, url: url})
+// description. The kind of link can be given with the LinkType option.
+func (s *Status) AddLink(description, url string, opts ...LinkOption) *Status {
+	l := link{description: description, url: url}
+	for _, opt := range opts {
+		opt(&l)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links = append(s.links, l)
 	return s
 }
 
 // SetRevision sets the source control revision string, typically a git hash.
 func (s *Status) SetRevision(revision string) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.revision = revision
 	return s
 }
@@ -46,34 +77,127 @@ func (s *Status) SetRevision(revision string) *Status {
 // AddChecker adds a function that can check the applications health.
 // Multiple checkers are allowed.  The checker functions should be capable of
 // being called concurrently (with each other and with themselves).
-func (s *Status) AddChecker(name string, checkerFunc func(cr *CheckResponse)) *Status {
-	s.checkers = append(s.checkers, checker{name, checkerFunc})
+func (s *Status) AddChecker(name string, checkerFunc func(cr *CheckResponse), opts ...CheckerOption) *Status {
+	return s.AddCheckerContext(name, func(_ context.Context, cr *CheckResponse) {
+		checkerFunc(cr)
+	}, opts...)
+}
+
+// AddCheckerContext adds a context aware function that can check the
+// applications health. The context is cancelled when the check times out or
+// when the caller of the health check goes away, and checkers should return
+// promptly once that happens.
+func (s *Status) AddCheckerContext(name string, checkerFunc func(ctx context.Context, cr *CheckResponse), opts ...CheckerOption) *Status {
+	ch := &checker{name: name, checkFunc: checkerFunc}
+	for _, opt := range opts {
+		opt(ch)
+	}
+	s.mu.Lock()
+	s.checkers = append(s.checkers, ch)
+	s.mu.Unlock()
+
+	s.startBackgroundChecker(ch)
+	return s
+}
+
+// WithCheckTimeout sets the default time each checker is allowed to run for.
+// A checker that runs past its deadline is reported as unhealthy. Checkers
+// added with the CheckerTimeout option use their own timeout instead. A zero
+// duration, the default, means checkers have no deadline of their own.
+func (s *Status) WithCheckTimeout(d time.Duration) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkTimeout = d
 	return s
 }
 
 // RemoveCheckers will remove health check functions added by AddChecker.
 // If multiple checks have been added with the same name, these will all be removed.
 func (s *Status) RemoveCheckers(name string) *Status {
-	var checkers []checker
+	s.mu.Lock()
+	var checkers, removed []*checker
 	for _, ch := range s.checkers {
 		if ch.name != name {
 			checkers = append(checkers, ch)
+		} else {
+			removed = append(removed, ch)
 		}
 	}
 	s.checkers = checkers
+	s.mu.Unlock()
+
+	s.stopBackgroundCheckers(removed)
 	return s
 }
 
 // AddMetrics registers prometheus metrics to be exopsed on the /__/metrics endpoint
-// Adding the same metric twice will result in a panic
+// Metrics that fail to register are logged and otherwise ignored. This
+// includes a collector describing the same metrics as one already registered,
+// as updates to it would never be exported. Adding the same collector twice is
+// fine.
 func (s *Status) AddMetrics(cs ...prometheus.Collector) *Status {
-	prometheus.MustRegister(cs...)
+	for _, c := range cs {
+		if existing := s.register(c); !sameCollector(existing, c) {
+			slog.Error(fmt.Sprintf("failed to register metrics: a different collector with the same metrics is already registered, updates to %T will not be exported", c))
+		}
+	}
+	return s
+}
+
+// sameCollector reports whether a and b are the same collector, without
+// panicking on collectors of types that can't be compared.
+func sameCollector(a, b prometheus.Collector) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// WithRegistry sets the prometheus registry that metrics are registered with
+// and that the /__/metrics endpoint serves, instead of the global default
+// registry. It should be called before AddMetrics or WithInstrumentedChecks.
+func (s *Status) WithRegistry(registerer prometheus.Registerer, gatherer prometheus.Gatherer) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registerer = registerer
+	s.gatherer = gatherer
 	return s
 }
 
+// Registry returns the prometheus registerer and gatherer used by the status.
+func (s *Status) Registry() (prometheus.Registerer, prometheus.Gatherer) {
+	s.mu.RLock()
+	registerer, gatherer := s.registerer, s.gatherer
+	s.mu.RUnlock()
+
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	return registerer, gatherer
+}
+
+// register registers c, returning the equivalent collector that is already
+// registered if there is one.
+func (s *Status) register(c prometheus.Collector) prometheus.Collector {
+	registerer, _ := s.Registry()
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector
+		}
+		slog.Error(fmt.Sprintf("failed to register metrics: %v", err))
+	}
+	return c
+}
+
 // ReadyNone indicates that this application doesn't expose a concept of
 // readiness.
 func (s *Status) ReadyNone() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.ready = nil
 	return s
 }
@@ -81,6 +205,8 @@ func (s *Status) ReadyNone() *Status {
 // ReadyAlways indicates that this application is always ready, typically if it
 // has no external systems to depend upon.
 func (s *Status) ReadyAlways() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.ready = func() bool { return true }
 	return s
 }
@@ -88,6 +214,8 @@ func (s *Status) ReadyAlways() *Status {
 // ReadyNever indicates that this application is never ready. Typically this is
 // only useful in testing.
 func (s *Status) ReadyNever() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.ready = func() bool { return false }
 	return s
 }
@@ -96,6 +224,8 @@ func (s *Status) ReadyNever() *Status {
 // re-use the health check. If the health is "ready" or "degraded" the
 // application is considered ready.
 func (s *Status) ReadyUseHealthCheck() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.ready = func() bool {
 		switch s.Check().Health {
 		case healthy:
@@ -111,82 +241,412 @@ func (s *Status) ReadyUseHealthCheck() *Status {
 
 // Ready allows specifying any readiness function.
 func (s *Status) Ready(f func() bool) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.ready = f
 	return s
 }
 
+// AddReadyChecker adds a function that checks a condition the application
+// needs before it is ready, such as a cache having been loaded. Ready checkers
+// are separate from health checkers and do not show up in the health check.
+// The application is ready when the readiness function, if any, reports ready
+// and every ready checker reports healthy or degraded.
+func (s *Status) AddReadyChecker(name string, checkerFunc func(ctx context.Context, cr *CheckResponse), opts ...CheckerOption) *Status {
+	ch := &checker{name: name, checkFunc: checkerFunc}
+	for _, opt := range opts {
+		opt(ch)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readyCheckers = append(s.readyCheckers, ch)
+	return s
+}
+
+// RemoveReadyCheckers will remove ready check functions added by
+// AddReadyChecker. If multiple checks have been added with the same name,
+// these will all be removed.
+func (s *Status) RemoveReadyCheckers(name string) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var checkers []*checker
+	for _, ch := range s.readyCheckers {
+		if ch.name != name {
+			checkers = append(checkers, ch)
+		}
+	}
+	s.readyCheckers = checkers
+	return s
+}
+
+// hasReadiness reports whether the application exposes a concept of
+// readiness, either with a readiness function or ready checkers.
+func (s *Status) hasReadiness() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ready != nil || len(s.readyCheckers) > 0 || s.drained || s.shuttingDown
+}
+
+// CheckReady returns the current readiness of the application, along with
+// the result of each ready checker. Each ready checker is run concurrently.
+func (s *Status) CheckReady(ctx context.Context) ReadyResult {
+	s.mu.RLock()
+	ready, checkers := s.ready, s.readyCheckers
+	drained, drainReason := s.drained, s.drainReason
+	shuttingDown := s.shuttingDown
+	s.mu.RUnlock()
+
+	if shuttingDown {
+		// Don't bother running the ready checkers, nothing will make the
+		// application ready again.
+		return ReadyResult{ShuttingDown: true, CheckResults: []healthResultEntry{}}
+	}
+
+	rr := ReadyResult{
+		CheckResults: s.runCheckersUnrecorded(ctx, checkers),
+	}
+	if drained {
+		rr.Drained = &drainResponse{Reason: drainReason}
+		return rr
+	}
+
+	rr.Ready = (ready != nil || len(checkers) > 0) && passing(rr.CheckResults)
+	if rr.Ready && ready != nil {
+		rr.Ready = ready()
+	}
+	return rr
+}
+
+// hasCheckers reports whether any health checkers have been added.
+func (s *Status) hasCheckers() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.checkers) > 0
+}
+
 // Check returns the current health state of the application. Each checker is
 // run concurrently.
 func (s *Status) Check() HealthResult {
+	return s.CheckContext(context.Background())
+}
+
+// CheckContext returns the current health state of the application. Each
+// checker is run concurrently and is cancelled along with ctx. When background
+// checks are enabled the last cached result of each checker is returned
+// instead.
+func (s *Status) CheckContext(ctx context.Context) HealthResult {
+	s.mu.RLock()
+	checkers, background := s.checkers, s.backgroundInterval > 0
+	s.mu.RUnlock()
+
 	hr := HealthResult{
 		Name:         s.name,
 		Description:  s.description,
-		CheckResults: make([]healthResultEntry, len(s.checkers)),
+		CheckResults: make([]healthResultEntry, len(checkers)),
 	}
 
+	if background {
+		for i, ch := range checkers {
+			hr.CheckResults[i] = s.cachedResult(ch)
+		}
+	} else {
+		var wg sync.WaitGroup
+		wg.Add(len(checkers))
+
+		for i, ch := range checkers {
+			go func(i int, ch *checker) {
+				defer wg.Done()
+				hr.CheckResults[i], _ = s.runCheck(ctx, ch)
+			}(i, ch)
+		}
+
+		wg.Wait()
+	}
+
+	overrides := s.activeOverrides()
+	for i, e := range hr.CheckResults {
+		if o, ok := overrides[e.Name]; ok {
+			hr.CheckResults[i].Override = o.response(e.Health)
+			hr.CheckResults[i].Health = o.health
+		}
+	}
+
+	hr.Health = aggregateHealth(checkers, hr.CheckResults)
+	if o, ok := overrides[""]; ok {
+		hr.Override = o.response(hr.Health)
+		hr.Health = o.health
+	}
+	return hr
+}
+
+// runCheckersUnrecorded runs each checker concurrently, without recording the
+// results in the health check metrics or logs.
+func (s *Status) runCheckersUnrecorded(ctx context.Context, checkers []*checker) []healthResultEntry {
+	entries := make([]healthResultEntry, len(checkers))
+
 	var wg sync.WaitGroup
-	wg.Add(len(s.checkers))
+	wg.Add(len(checkers))
 
-	for i, ch := range s.checkers {
-		go func(i int, ch checker) {
+	for i, ch := range checkers {
+		go func(i int, ch *checker) {
 			defer wg.Done()
-
-			var cr CheckResponse
-			ch.checkFunc(&cr)
-			hr.CheckResults[i] = healthResultEntry{
-				Name:   ch.name,
-				Health: cr.health,
-				Output: cr.output,
-				Action: cr.action,
-				Impact: cr.impact,
-			}
-			s.updateCheckMetrics(ch, cr)
-			s.logCheckResult(ch, cr)
+			cr, _ := s.runChecker(ctx, ch)
+			entries[i] = newHealthResultEntry(ch, cr)
 		}(i, ch)
 	}
 
 	wg.Wait()
+	return entries
+}
 
+// passing reports whether every entry is either healthy or degraded.
+func passing(entries []healthResultEntry) bool {
+	for _, e := range entries {
+		if e.Health != healthy && e.Health != degraded {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregateHealth works out the overall health from the result of each
+// checker. Unhealthy results of non critical checkers only degrade it.
+func aggregateHealth(checkers []*checker, entries []healthResultEntry) string {
 	var seenHealthy, seenDegraded, seenUnhealthy bool
-	for _, hcr := range hr.CheckResults {
+	for i, hcr := range entries {
 		switch hcr.Health {
 		case healthy:
 			seenHealthy = true
 		case degraded:
 			seenDegraded = true
-		case unhealthy:
-			seenUnhealthy = true
+		case unhealthy, unknown:
+			if checkers[i].nonCritical {
+				seenDegraded = true
+			} else {
+				seenUnhealthy = true
+			}
 		}
 	}
 
 	switch {
 	case seenUnhealthy:
-		hr.Health = unhealthy
+		return unhealthy
 	case seenDegraded:
-		hr.Health = degraded
+		return degraded
 	case seenHealthy:
-		hr.Health = healthy
+		return healthy
 	default:
 		// We have no health checks. Assume unhealthy.
-		hr.Health = unhealthy
+		return unhealthy
 	}
+}
 
-	return hr
+// runCheck runs a single checker and records its outcome in the metrics and
+// logs. It reports whether the outcome was recorded, which it is not when ctx
+// is done before the check finishes.
+func (s *Status) runCheck(ctx context.Context, ch *checker) (healthResultEntry, bool) {
+	start := time.Now()
+	cr, cancelled := s.runChecker(ctx, ch)
+	return s.recordCheck(ch, cr, start, time.Since(start), cancelled), !cancelled
+}
+
+// recordCheck records the outcome of a checker run in the metrics, logs,
+// history and hysteresis counters, unless it was cancelled, and returns the
+// entry to report.
+func (s *Status) recordCheck(ch *checker, cr CheckResponse, start time.Time, duration time.Duration, cancelled bool) healthResultEntry {
+	e := newHealthResultEntry(ch, cr)
+	if cancelled {
+		// A run cancelled by its caller says nothing about the health of
+		// the check, so it is not recorded.
+		return e
+	}
+
+	s.updateCheckMetrics(ch, cr, start, duration)
+	s.logCheckResult(ch, cr)
+	flapping := s.recordHistory(ch, e, start, duration)
+	e = ch.smooth(e)
+	if flapping {
+		markFlapping(&e)
+	}
+	s.mu.RLock()
+	timings, background := s.checkTimings, s.backgroundInterval > 0
+	s.mu.RUnlock()
+
+	if timings || background {
+		e.LastRun = &start
+	}
+	if timings {
+		e.Duration = duration.Seconds()
+	}
+	return e
+}
+
+func newHealthResultEntry(ch *checker, cr CheckResponse) healthResultEntry {
+	return healthResultEntry{
+		Name:       ch.name,
+		Health:     cr.health,
+		Output:     cr.output,
+		Action:     cr.action,
+		Impact:     cr.impact,
+		Runbook:    cr.runbook,
+		Dashboard:  cr.dashboard,
+		Dependency: cr.dependency,
+		ErrorCode:  cr.errorCode,
+		Labels:     cr.labels,
+	}
+}
+
+// WithUnknownChecks reports checkers that return without calling Healthy,
+// Degraded or Unhealthy as "unknown" rather than "unhealthy", so that a broken
+// checker can be told apart from a broken dependency. Either way the
+// application is considered unhealthy.
+func (s *Status) WithUnknownChecks() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unknownChecks = true
+	return s
+}
+
+// unreported fills in the response of a checker that didn't report a result.
+func (s *Status) unreported(cr *CheckResponse) {
+	cr.Unhealthy("check did not report a result",
+		"fix the check so that it calls Healthy, Degraded or Unhealthy",
+		"the health of this dependency is not known")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.unknownChecks {
+		cr.health = unknown
+	}
+}
+
+// runChecker runs a single checker, giving up on it once its timeout passes
+// or ctx is done. A checker that is given up on keeps running in the
+// background, but its result is discarded. A panicking checker is reported as
+// unhealthy. The returned bool reports whether ctx was done, meaning the
+// caller cancelled the check, as opposed to the check timing out.
+func (s *Status) runChecker(ctx context.Context, ch *checker) (CheckResponse, bool) {
+	parent := ctx
+	timeout := ch.timeout
+	if timeout == 0 {
+		s.mu.RLock()
+		timeout = s.checkTimeout
+		s.mu.RUnlock()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	ch.mu.Lock()
+	if ch.running == 0 {
+		ch.runningSince = time.Now()
+	}
+	ch.running++
+	ch.mu.Unlock()
+
+	done := make(chan CheckResponse, 1)
+	go func() {
+		var cr CheckResponse
+		defer func() {
+			ch.mu.Lock()
+			ch.running--
+			ch.mu.Unlock()
+
+			if r := recover(); r != nil {
+				s.countCheckPanic(ch)
+				cr.Unhealthy(fmt.Sprintf("check panicked: %v\n%s", r, panicStack()), "", "")
+			}
+			done <- cr
+		}()
+		ch.checkFunc(ctx, &cr)
+	}()
+
+	select {
+	case cr := <-done:
+		if cr.health == "" {
+			s.unreported(&cr)
+		}
+		return cr, false
+	case <-ctx.Done():
+		var cr CheckResponse
+		if parent.Err() != nil {
+			cr.Unhealthy(fmt.Sprintf("check cancelled: %v", parent.Err()), "", "")
+			return cr, true
+		}
+		cr.Unhealthy(fmt.Sprintf("check timed out after %s", timeout), "", "")
+		return cr, false
+	}
+}
+
+// panicStack returns the stack trace of a recovered panic, starting at the
+// frame that panicked and limited to maxPanicStackLines lines.
+func panicStack() string {
+	lines := strings.Split(strings.TrimSpace(string(debug.Stack())), "\n")
+	// Skip the goroutine header and the frames of the recovery itself, which
+	// end with the call to panic.
+	for i, l := range lines {
+		if strings.HasPrefix(l, "panic(") {
+			lines = lines[i+2:]
+			break
+		}
+	}
+	if len(lines) > maxPanicStackLines {
+		lines = lines[:maxPanicStackLines]
+	}
+	return strings.Join(lines, "\n")
 }
 
 // WithInstrumentedChecks enables the outcome of healthchecks to be instrumented as a counter
+// The metrics are registered with the registry set by WithRegistry, if any.
 func (s *Status) WithInstrumentedChecks() *Status {
 	checkGaugeVec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: healthcheckStatus,
 		Help: "Meters the healthcheck status based for each check and for each result",
 	}, []string{healthcheckName, healthcheckResult})
+	checkPanicCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: healthcheckPanics,
+		Help: "Counts the number of times each healthcheck has panicked",
+	}, []string{healthcheckName})
+	checkDurationHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: healthcheckDuration,
+		Help: "Measures how long each healthcheck takes to run",
+	}, []string{healthcheckName})
+	checkLastSuccessGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: healthcheckLastSuccess,
+		Help: "Unix time each healthcheck last reported healthy or degraded",
+	}, []string{healthcheckName})
+
+	checkGaugeVec, _ = s.register(checkGaugeVec).(*prometheus.GaugeVec)
+	checkPanicCounter, _ = s.register(checkPanicCounter).(*prometheus.CounterVec)
+	checkDurationHistogram, _ = s.register(checkDurationHistogram).(*prometheus.HistogramVec)
+	checkLastSuccessGauge, _ = s.register(checkLastSuccessGauge).(*prometheus.GaugeVec)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.checkResultGauge = checkGaugeVec
-	prometheus.MustRegister(s.checkResultGauge)
+	s.checkPanicCounter = checkPanicCounter
+	s.checkDurationHistogram = checkDurationHistogram
+	s.checkLastSuccessGauge = checkLastSuccessGauge
+	return s
+}
+
+// WithCheckTimings adds the time each check was run, and how long it took,
+// to its result.
+func (s *Status) WithCheckTimings() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkTimings = true
 	return s
 }
 
 // WithChecksLogger enables the outcome of healthchecks to be logged
 func (s *Status) WithChecksLogger() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.loggerEnabled = true
 	return s
 }
@@ -203,9 +663,23 @@ func safeMetricName(checkName string) string {
 	return x
 }
 
-func (s *Status) updateCheckMetrics(checker checker, cr CheckResponse) {
+func (s *Status) updateCheckMetrics(checker *checker, cr CheckResponse, start time.Time, duration time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	labels := map[string]string{healthcheckName: safeMetricName(checker.name)}
+	if s.checkDurationHistogram != nil {
+		s.checkDurationHistogram.With(labels).Observe(duration.Seconds())
+	}
+	if s.checkLastSuccessGauge != nil && (cr.health == healthy || cr.health == degraded) {
+		s.checkLastSuccessGauge.With(labels).Set(float64(start.UnixNano()) / 1e9)
+	}
+
 	if s.checkResultGauge != nil {
 		possibleStatuses := []string{healthy, unhealthy, degraded}
+		if s.unknownChecks {
+			possibleStatuses = append(possibleStatuses, unknown)
+		}
 		for _, status := range possibleStatuses {
 			if cr.health == status {
 				s.checkResultGauge.With(map[string]string{healthcheckName: safeMetricName(checker.name), healthcheckResult: status}).Set(1)
@@ -216,11 +690,24 @@ func (s *Status) updateCheckMetrics(checker checker, cr CheckResponse) {
 	}
 }
 
-func (s *Status) logCheckResult(checker checker, cr CheckResponse) {
-	if s.loggerEnabled {
+func (s *Status) countCheckPanic(checker *checker) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.checkPanicCounter != nil {
+		s.checkPanicCounter.With(map[string]string{healthcheckName: safeMetricName(checker.name)}).Inc()
+	}
+}
+
+func (s *Status) logCheckResult(checker *checker, cr CheckResponse) {
+	s.mu.RLock()
+	loggerEnabled := s.loggerEnabled
+	s.mu.RUnlock()
+
+	if loggerEnabled {
 		logMsg := fmt.Sprintf("[%s] health-check is [%s]", checker.name, cr.health)
 		switch cr.health {
-		case unhealthy:
+		case unhealthy, unknown:
 			slog.Error(logMsg)
 		case degraded:
 			slog.Warn(logMsg)
@@ -232,17 +719,36 @@ func (s *Status) logCheckResult(checker checker, cr CheckResponse) {
 
 // About returns static information about this application or service.
 func (s *Status) About() AboutResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	about := AboutResponse{
 		Name:        s.name,
 		Description: s.description,
-		BuildInfo:   buildInfoResponse{Revision: s.revision},
+		Instance:    s.instanceResponse(),
+	}
+	if s.buildInfo != nil {
+		about.BuildInfo = *s.buildInfo
+	}
+	if s.revision != "" {
+		about.BuildInfo.Revision = s.revision
 	}
 
 	for _, l := range s.links {
-		about.Links = append(about.Links, linkResponse{l.description, l.url})
+		about.Links = append(about.Links, linkResponse{
+			Description: l.description,
+			URL:         l.url,
+			Type:        l.linkType,
+		})
 	}
 	for _, o := range s.owners {
-		about.Owners = append(about.Owners, ownerResponse{o.name, o.slack})
+		about.Owners = append(about.Owners, ownerResponse{
+			Name:      o.name,
+			Slack:     o.slack,
+			Email:     o.email,
+			PagerDuty: o.pagerDuty,
+			Role:      o.role,
+		})
 	}
 	return about
 }
@@ -250,25 +756,67 @@ func (s *Status) About() AboutResponse {
 // Status represents standard operational information about an application,
 // including how to establish dynamic information such as health or readiness.
 type Status struct {
-	name             string
-	description      string
-	owners           []owner
-	links            []link
-	revision         string
-	checkers         []checker
-	ready            func() bool
-	checkResultGauge *prometheus.GaugeVec
-	loggerEnabled    bool
+	// mu guards the configuration below, which may be changed while the
+	// status is being served.
+	mu sync.RWMutex
+
+	name         string
+	description  string
+	owners       []owner
+	links        []link
+	revision     string
+	buildInfo    *buildInfoResponse
+	startTime    time.Time
+	instanceInfo *instanceInfo
+	checkers     []*checker
+	checkTimeout time.Duration
+
+	backgroundInterval time.Duration
+	backgroundMaxAge   time.Duration
+	backgroundMu       sync.Mutex
+	backgroundCtx      context.Context
+	backgroundCancel   context.CancelFunc
+	backgroundWG       sync.WaitGroup
+
+	historySize int
+	flapChanges int
+	flapWindow  time.Duration
+
+	ready                  func() bool
+	readyCheckers          []*checker
+	liveCheckers           []*checker
+	startupMu              sync.Mutex
+	startupGates           []*startupGate
+	registerer             prometheus.Registerer
+	gatherer               prometheus.Gatherer
+	checkResultGauge       *prometheus.GaugeVec
+	checkPanicCounter      *prometheus.CounterVec
+	checkDurationHistogram *prometheus.HistogramVec
+	checkLastSuccessGauge  *prometheus.GaugeVec
+	checkTimings           bool
+	loggerEnabled          bool
+	overrides              map[string]override
+	overrideAuthorize      func(r *http.Request) bool
+	drained                bool
+	drainReason            string
+	shuttingDown           bool
+	shutdownHooks          []shutdownHook
+	shutdownDelay          time.Duration
+	unknownChecks          bool
 }
 
 type owner struct {
-	name  string
-	slack string
+	name      string
+	slack     string
+	email     string
+	pagerDuty string
+	role      string
 }
 
 type link struct {
 	description string
 	url         string
+	linkType    string
 }
 
 // AboutResponse represents the static "about" information for an application
@@ -280,25 +828,78 @@ type AboutResponse struct {
 	Owners      []ownerResponse   `json:"owners"`
 	Links       []linkResponse    `json:"links,omitempty"`
 	BuildInfo   buildInfoResponse `json:"build-info"`
+	Instance    *instanceResponse `json:"instance,omitempty"`
 }
 
 type ownerResponse struct {
-	Name  string `json:"name"`
-	Slack string `json:"slack,omitempty"`
+	Name      string `json:"name"`
+	Slack     string `json:"slack,omitempty"`
+	Email     string `json:"email,omitempty"`
+	PagerDuty string `json:"pagerduty,omitempty"`
+	Role      string `json:"role,omitempty"`
 }
 
 type linkResponse struct {
 	Description string `json:"description"`
 	URL         string `json:"url"`
+	Type        string `json:"type,omitempty"`
 }
 
 type buildInfoResponse struct {
-	Revision string `json:"revision"`
+	Revision     string            `json:"revision"`
+	Time         string            `json:"time,omitempty"`
+	Modified     bool              `json:"modified,omitempty"`
+	GoVersion    string            `json:"go-version,omitempty"`
+	Path         string            `json:"path,omitempty"`
+	Version      string            `json:"version,omitempty"`
+	Dependencies map[string]string `json:"dependencies,omitempty"`
 }
 
 type checker struct {
-	name      string
-	checkFunc func(resp *CheckResponse)
+	name        string
+	checkFunc   func(ctx context.Context, resp *CheckResponse)
+	timeout     time.Duration
+	interval    time.Duration
+	nonCritical bool
+
+	failureThreshold int
+	successThreshold int
+
+	// mu guards the state of the checker between runs
+	mu          sync.Mutex
+	cached      *healthResultEntry
+	stop        context.CancelFunc
+	history     []HistoryEntry
+	historyNext int
+	lastHealth  string
+	transitions []time.Time
+	smoothed    *healthResultEntry
+	failures    int
+	successes   int
+	running     int
+	// runningSince is when running last went from zero to one
+	runningSince time.Time
+}
+
+// CheckerOption configures a single checker added with AddChecker or
+// AddCheckerContext.
+type CheckerOption func(*checker)
+
+// NonCritical marks the checker as checking an optional dependency. When it
+// reports unhealthy the application is only considered degraded, while the
+// checker's own result is still reported as unhealthy.
+func NonCritical() CheckerOption {
+	return func(ch *checker) {
+		ch.nonCritical = true
+	}
+}
+
+// CheckerTimeout sets how long the checker is allowed to run for before it is
+// reported as unhealthy, overriding the default set with WithCheckTimeout.
+func CheckerTimeout(d time.Duration) CheckerOption {
+	return func(ch *checker) {
+		ch.timeout = d
+	}
 }
 
 // CheckResponse is used by a health check function to allow it to indicate
@@ -308,6 +909,12 @@ type CheckResponse struct {
 	output string
 	action string
 	impact string
+
+	runbook    string
+	dashboard  string
+	dependency string
+	errorCode  string
+	labels     map[string]string
 }
 
 // Healthy indicates that the check reports good health. The output of the check
@@ -337,6 +944,37 @@ func (cr *CheckResponse) Unhealthy(output, action, impact string) {
 	cr.impact = impact
 }
 
+// SetRunbook attaches the URL of a runbook for remediating the check to the
+// result.
+func (cr *CheckResponse) SetRunbook(url string) {
+	cr.runbook = url
+}
+
+// SetDashboard attaches the URL of a dashboard relevant to the check to the
+// result.
+func (cr *CheckResponse) SetDashboard(url string) {
+	cr.dashboard = url
+}
+
+// SetDependency attaches the name of the dependency being checked to the
+// result.
+func (cr *CheckResponse) SetDependency(name string) {
+	cr.dependency = name
+}
+
+// SetErrorCode attaches a machine readable code for the failure to the result.
+func (cr *CheckResponse) SetErrorCode(code string) {
+	cr.errorCode = code
+}
+
+// SetLabel attaches an arbitrary key/value label to the result.
+func (cr *CheckResponse) SetLabel(key, value string) {
+	if cr.labels == nil {
+		cr.labels = map[string]string{}
+	}
+	cr.labels[key] = value
+}
+
 // HealthResult represents the current "health" information for an application
 // as described in the UW operation endpoints spec.  When serialised to JSON
 // it is compiant with that spec.
@@ -344,13 +982,41 @@ type HealthResult struct {
 	Name         string              `json:"name"`
 	Description  string              `json:"description"`
 	Health       string              `json:"health"`
+	Override     *overrideResponse   `json:"override,omitempty"`
 	CheckResults []healthResultEntry `json:"checks"`
 }
 
+// ReadyResult represents the current readiness of an application, along with
+// the result of each of its ready checkers.
+type ReadyResult struct {
+	Ready        bool                `json:"ready"`
+	Drained      *drainResponse      `json:"drained,omitempty"`
+	ShuttingDown bool                `json:"shutting-down,omitempty"`
+	CheckResults []healthResultEntry `json:"checks"`
+}
+
+type drainResponse struct {
+	Reason string `json:"reason"`
+}
+
 type healthResultEntry struct {
 	Name   string `json:"name"`
 	Health string `json:"health"`
 	Output string `json:"output"`
 	Action string `json:"action,omitempty"`
 	Impact string `json:"impact,omitempty"`
+
+	Runbook    string            `json:"runbook,omitempty"`
+	Dashboard  string            `json:"dashboard,omitempty"`
+	Dependency string            `json:"dependency,omitempty"`
+	ErrorCode  string            `json:"error-code,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+
+	LastRun  *time.Time `json:"last-run,omitempty"`
+	Stale    bool       `json:"stale,omitempty"`
+	Flapping bool       `json:"flapping,omitempty"`
+	Duration float64    `json:"duration-seconds,omitempty"`
+
+	Raw      *rawResult        `json:"raw,omitempty"`
+	Override *overrideResponse `json:"override,omitempty"`
 }
@@ -0,0 +1,159 @@
+package op
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// maxOverrideRequestSize limits the size of a request to the override
+// endpoint.
+const maxOverrideRequestSize = 64 << 10
+
+// OverrideCheck pins the named check to the given health, one of "healthy",
+// "degraded" or "unhealthy", until expires, for example during planned
+// maintenance. A zero expires pins it until ClearOverride is called. The
+// check still runs, and its actual health is reported alongside the reason.
+// It returns an error if no check with that name has been added.
+func (s *Status) OverrideCheck(name, health, reason string, expires time.Time) error {
+	if name == "" {
+		return fmt.Errorf("check name must not be empty")
+	}
+	return s.setOverride(name, health, reason, expires)
+}
+
+// OverrideHealth pins the overall health of the application to the given
+// health, one of "healthy", "degraded" or "unhealthy", until expires. A zero
+// expires pins it until ClearOverride("") is called.
+func (s *Status) OverrideHealth(health, reason string, expires time.Time) error {
+	return s.setOverride("", health, reason, expires)
+}
+
+// ClearOverride removes the override of the named check, or of the overall
+// health if name is empty.
+func (s *Status) ClearOverride(name string) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.overrides, name)
+	return s
+}
+
+func (s *Status) setOverride(name, health, reason string, expires time.Time) error {
+	switch health {
+	case healthy, degraded, unhealthy:
+	default:
+		return fmt.Errorf("invalid health %q, must be one of %s, %s or %s", health, healthy, degraded, unhealthy)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name != "" && !s.hasCheckerLocked(name) {
+		return fmt.Errorf("unknown check %q", name)
+	}
+	if s.overrides == nil {
+		s.overrides = map[string]override{}
+	}
+	s.overrides[name] = override{health: health, reason: reason, expires: expires}
+	return nil
+}
+
+func (s *Status) hasCheckerLocked(name string) bool {
+	for _, ch := range s.checkers {
+		if ch.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// activeOverrides returns the overrides that have not expired.
+func (s *Status) activeOverrides() map[string]override {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	active := map[string]override{}
+	for name, o := range s.overrides {
+		if o.expires.IsZero() || now.Before(o.expires) {
+			active[name] = o
+		}
+	}
+	return active
+}
+
+// Drain takes the application out of service by making it report not ready,
+// whatever its readiness function and ready checkers say, until Undrain is
+// called.
+func (s *Status) Drain(reason string) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.drained = true
+	s.drainReason = reason
+	return s
+}
+
+// Undrain reverses Drain.
+func (s *Status) Undrain() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.drained = false
+	s.drainReason = ""
+	return s
+}
+
+// WithOverrideEndpoint enables the POST /__/health/override endpoint for
+// setting and clearing overrides, and draining the application, at runtime.
+// Each request must be allowed by authorize, see BearerToken.
+func (s *Status) WithOverrideEndpoint(authorize func(r *http.Request) bool) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.overrideAuthorize = authorize
+	return s
+}
+
+// BearerToken returns a function for WithOverrideEndpoint that only allows
+// requests with an "Authorization: Bearer <token>" header.
+func BearerToken(token string) func(r *http.Request) bool {
+	expected := []byte("Bearer " + token)
+	return func(r *http.Request) bool {
+		return subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) == 1
+	}
+}
+
+func (o override) response(actual string) *overrideResponse {
+	or := &overrideResponse{Reason: o.reason, ActualHealth: actual}
+	if !o.expires.IsZero() {
+		expires := o.expires
+		or.Expires = &expires
+	}
+	return or
+}
+
+type override struct {
+	health  string
+	reason  string
+	expires time.Time
+}
+
+type overrideResponse struct {
+	Reason       string     `json:"reason"`
+	Expires      *time.Time `json:"expires,omitempty"`
+	ActualHealth string     `json:"actual-health"`
+}
+
+// overrideRequest is the body of a request to the override endpoint. An
+// empty check applies to the overall health.
+type overrideRequest struct {
+	Check   string    `json:"check"`
+	Health  string    `json:"health"`
+	Reason  string    `json:"reason"`
+	Expires time.Time `json:"expires"`
+	Clear   bool      `json:"clear"`
+	Drain   *bool     `json:"drain"`
+}
@@ -0,0 +1,133 @@
+package op
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrideCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").
+		AddChecker("database", func(cr *CheckResponse) {
+			cr.Unhealthy("database is down", "wait for maintenance to finish", "nothing works")
+		}).
+		AddChecker("cache", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		})
+
+	expires := time.Now().Add(time.Hour)
+	assert.NoError(hc.OverrideCheck("database", "degraded", "planned maintenance", expires))
+
+	result := hc.Check()
+	assert.Equal("degraded", result.Health)
+	assert.Equal("degraded", result.CheckResults[0].Health)
+	assert.Equal(&overrideResponse{Reason: "planned maintenance", Expires: &expires, ActualHealth: "unhealthy"}, result.CheckResults[0].Override)
+	assert.Nil(result.CheckResults[1].Override)
+
+	hc.ClearOverride("database")
+	assert.Equal("unhealthy", hc.Check().Health)
+
+	assert.NoError(hc.OverrideCheck("database", "healthy", "expired", time.Now().Add(-time.Second)))
+	assert.Equal("unhealthy", hc.Check().Health, "expired overrides should be ignored")
+
+	assert.Error(hc.OverrideCheck("database", "fine", "bad health", time.Time{}))
+	assert.Error(hc.OverrideCheck("", "healthy", "no name", time.Time{}))
+	assert.Error(hc.OverrideCheck("queue", "healthy", "unknown check", time.Time{}))
+}
+
+func TestOverrideHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").
+		AddChecker("database", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		})
+
+	assert.NoError(hc.OverrideHealth("unhealthy", "maintenance mode", time.Time{}))
+
+	result := hc.Check()
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal(&overrideResponse{Reason: "maintenance mode", ActualHealth: "healthy"}, result.Override)
+	assert.Equal("healthy", result.CheckResults[0].Health)
+
+	hc.ClearOverride("")
+	assert.Equal("healthy", hc.Check().Health)
+}
+
+func TestDrain(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").ReadyAlways()
+	assert.True(hc.CheckReady(context.Background()).Ready)
+
+	hc.Drain("moving traffic away")
+	result := hc.CheckReady(context.Background())
+	assert.False(result.Ready)
+	assert.Equal(&drainResponse{Reason: "moving traffic away"}, result.Drained)
+
+	hc.Undrain()
+	assert.True(hc.CheckReady(context.Background()).Ready)
+}
+
+func TestOverrideHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	st := NewStatus("my app", "app description").
+		ReadyAlways().
+		AddChecker("database", func(cr *CheckResponse) {
+			cr.Healthy("ok")
+		})
+	h := NewHandler(st)
+
+	post := func(token, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/__/health/override", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(http.StatusNotFound, post("secret", `{}`), "Expected 404 when the endpoint is not enabled")
+
+	st.WithOverrideEndpoint(BearerToken("secret"))
+	expires := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	assert.Equal(http.StatusUnauthorized, post("", `{"check": "database", "health": "unhealthy"}`))
+	assert.Equal(http.StatusUnauthorized, post("wrong", `{"check": "database", "health": "unhealthy"}`))
+	assert.Equal(http.StatusBadRequest, post("secret", `{"check": "database", "health": "broken"}`))
+	assert.Equal(http.StatusBadRequest, post("secret", `not json`))
+	assert.Equal(http.StatusBadRequest, post("secret", `{"check": "database", "health": "unhealthy", "reason": "forever"}`), "Expected 400 without expires")
+	assert.Equal(http.StatusBadRequest, post("secret", `{"check": "cache", "health": "unhealthy", "expires": "`+expires+`"}`), "Expected 400 for an unknown check")
+	assert.Equal(http.StatusBadRequest, post("secret", `{"reason": "`+strings.Repeat("x", maxOverrideRequestSize)+`"}`), "Expected 400 for an oversized body")
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/__/health/override", nil))
+	assert.Equal(http.StatusMethodNotAllowed, rr.Code)
+
+	assert.Equal(http.StatusNoContent, post("secret", `{"check": "database", "health": "unhealthy", "reason": "failover test", "expires": "`+expires+`"}`))
+	result := st.Check()
+	assert.Equal("unhealthy", result.Health)
+	assert.Equal("failover test", result.CheckResults[0].Override.Reason)
+
+	assert.Equal(http.StatusNoContent, post("secret", `{"check": "database", "clear": true}`))
+	assert.Equal("healthy", st.Check().Health)
+
+	assert.Equal(http.StatusNoContent, post("secret", `{"drain": true, "reason": "node maintenance"}`))
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/__/ready", nil))
+	assert.Equal(http.StatusServiceUnavailable, rr.Code)
+
+	assert.Equal(http.StatusNoContent, post("secret", `{"drain": false}`))
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/__/ready", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+}
@@ -0,0 +1,52 @@
+package op
+
+// Owner roles, for use with OwnerRole.
+const (
+	OwnerRolePrimary   = "primary"
+	OwnerRoleSecondary = "secondary"
+)
+
+// Link types, for use with LinkType.
+const (
+	LinkTypeRunbook   = "runbook"
+	LinkTypeDashboard = "dashboard"
+	LinkTypeRepo      = "repo"
+	LinkTypeLogs      = "logs"
+)
+
+// OwnerOption adds details to an owner added with AddOwner.
+type OwnerOption func(*owner)
+
+// OwnerEmail sets the email address of the owner.
+func OwnerEmail(email string) OwnerOption {
+	return func(o *owner) {
+		o.email = email
+	}
+}
+
+// OwnerPagerDuty sets the PagerDuty service ID used to page the owner.
+func OwnerPagerDuty(serviceID string) OwnerOption {
+	return func(o *owner) {
+		o.pagerDuty = serviceID
+	}
+}
+
+// OwnerRole sets the role of the owner, typically OwnerRolePrimary or
+// OwnerRoleSecondary.
+func OwnerRole(role string) OwnerOption {
+	return func(o *owner) {
+		o.role = role
+	}
+}
+
+// LinkOption adds details to a link added with AddLink.
+type LinkOption func(*link)
+
+// LinkType sets what kind of link it is, typically one of LinkTypeRunbook,
+// LinkTypeDashboard, LinkTypeRepo or LinkTypeLogs, so that tools can render
+// it appropriately.
+func LinkType(linkType string) LinkOption {
+	return func(l *link) {
+		l.linkType = linkType
+	}
+}
@@ -0,0 +1,165 @@
+package op
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const serverCheckName = "operational server"
+
+// Server serves the operational endpoints of a Status on their own address,
+// usually a port separate from the one serving application traffic.
+type Server struct {
+	server          *http.Server
+	shutdownTimeout time.Duration
+	handlerOpts     []HandlerOption
+	healthCheck     bool
+
+	mu      sync.Mutex
+	addr    net.Addr
+	err     error
+	stopped bool
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// ServerTimeouts sets the read, write and idle timeouts of the underlying
+// http.Server. The write timeout should leave room for CPU profiles, which
+// take 30 seconds by default.
+func ServerTimeouts(read, write, idle time.Duration) ServerOption {
+	return func(s *Server) {
+		s.server.ReadTimeout = read
+		s.server.WriteTimeout = write
+		s.server.IdleTimeout = idle
+	}
+}
+
+// ServerShutdownTimeout sets how long in-flight requests are given to
+// complete once the server is shutting down. It defaults to 10 seconds.
+func ServerShutdownTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.shutdownTimeout = d
+	}
+}
+
+// ServerHealthCheck adds an "operational server" checker to the Status, which
+// reports unhealthy if the server fails to listen or serve, or has not been
+// started.
+func ServerHealthCheck() ServerOption {
+	return func(s *Server) {
+		s.healthCheck = true
+	}
+}
+
+// ServerHandlerOptions passes opts to NewHandler, for example to change the
+// prefix the endpoints are served under.
+func ServerHandlerOptions(opts ...HandlerOption) ServerOption {
+	return func(s *Server) {
+		s.handlerOpts = append(s.handlerOpts, opts...)
+	}
+}
+
+// NewServer returns a Server that serves NewHandler(status) on addr.
+func NewServer(addr string, status *Status, opts ...ServerOption) *Server {
+	s := &Server{
+		server: &http.Server{
+			Addr:              addr,
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      60 * time.Second,
+			IdleTimeout:       120 * time.Second,
+		},
+		shutdownTimeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.server.Handler = NewHandler(status, s.handlerOpts...)
+
+	if s.healthCheck {
+		// Replace the checker of any earlier server for this status.
+		status.RemoveCheckers(serverCheckName).AddChecker(serverCheckName, s.check)
+	}
+	return s
+}
+
+// ListenAndServe serves the operational endpoints on the server's address
+// until ctx is done, and then shuts the server down gracefully. It returns
+// nil after a clean shutdown.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		s.setErr(err)
+		return err
+	}
+
+	s.mu.Lock()
+	s.addr = ln.Addr()
+	s.mu.Unlock()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.server.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		s.setErr(err)
+		return err
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	err = s.server.Shutdown(shutdownCtx)
+	if serveErr := <-errc; !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return err
+}
+
+// Addr returns the address the server is listening on, or nil if it is not
+// listening yet. It is useful when listening on port 0.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+func (s *Server) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *Server) check(cr *CheckResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.err != nil:
+		cr.Unhealthy(fmt.Sprintf("operational server failed: %v", s.err), "check the operational address is free and the service logs", "operational endpoints are not being served")
+	case s.stopped:
+		cr.Healthy("operational server has shut down")
+	case s.addr == nil:
+		cr.Unhealthy("operational server has not started", "check the operational server is started", "operational endpoints are not being served")
+	default:
+		cr.Healthy(fmt.Sprintf("listening on %s", s.addr))
+	}
+}
+
+// ListenAndServe serves the operational endpoints of status on addr until
+// ctx is done. See Server for details.
+func ListenAndServe(ctx context.Context, addr string, status *Status, opts ...ServerOption) error {
+	return NewServer(addr, status, opts...).ListenAndServe(ctx)
+}
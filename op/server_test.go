@@ -0,0 +1,108 @@
+package op
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description").ReadyAlways()
+	srv := NewServer("127.0.0.1:0", hc, ServerHealthCheck())
+	result := hc.Check()
+	assert.Equal(unhealthy, result.Health)
+	assert.Equal("operational server has not started", result.CheckResults[0].Output)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.ListenAndServe(ctx)
+	}()
+
+	assert.Eventually(func() bool { return srv.Addr() != nil }, time.Second, time.Millisecond)
+
+	resp, err := http.Get("http://" + srv.Addr().String() + "/__/ready")
+	if !assert.NoError(err) {
+		return
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("ready\n", string(body))
+
+	result = hc.Check()
+	assert.Equal(healthy, result.Health)
+	assert.Equal("listening on "+srv.Addr().String(), result.CheckResults[0].Output)
+
+	cancel()
+	assert.NoError(<-done)
+}
+
+func TestServerListenError(t *testing.T) {
+	assert := assert.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(err) {
+		return
+	}
+	defer ln.Close()
+
+	hc := NewStatus("my app", "app description")
+	err = NewServer(ln.Addr().String(), hc, ServerHealthCheck()).ListenAndServe(context.Background())
+	assert.Error(err)
+
+	result := hc.Check()
+	assert.Equal(unhealthy, result.Health)
+	assert.Contains(result.CheckResults[0].Output, "operational server failed")
+}
+
+func TestServerKeepsDefaultServeMux(t *testing.T) {
+	assert := assert.New(t)
+
+	defaultMux := http.DefaultServeMux
+	defer func() { http.DefaultServeMux = defaultMux }()
+
+	http.DefaultServeMux = http.NewServeMux()
+	http.HandleFunc("/public", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "public")
+	})
+
+	NewServer("127.0.0.1:0", NewStatus("my app", "app description"))
+
+	rr := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/public", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("public", rr.Body.String())
+}
+
+func TestServerHealthCheckOptIn(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description")
+	NewServer("127.0.0.1:0", hc)
+	assert.False(hc.hasCheckers(), "server should not add a checker unless asked to")
+
+	NewServer("127.0.0.1:0", hc, ServerHealthCheck())
+	NewServer("127.0.0.1:0", hc, ServerHealthCheck())
+	assert.Len(hc.Check().CheckResults, 1)
+}
+
+func TestServerNoPprofOnDefaultServeMux(t *testing.T) {
+	assert := assert.New(t)
+
+	NewServer("127.0.0.1:0", NewStatus("my app", "app description"))
+
+	rr := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,124 @@
+package op
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// AddShutdownHook adds a function run by Shutdown, such as closing a
+// database connection. Hooks are run one at a time, in the order they were
+// added.
+func (s *Status) AddShutdownHook(name string, hook func(ctx context.Context) error) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdownHooks = append(s.shutdownHooks, shutdownHook{name: name, hook: hook})
+	return s
+}
+
+// WithShutdownDelay sets how long Shutdown waits, after the application has
+// started reporting not ready, before running the shutdown hooks. This gives
+// load balancers time to stop sending new requests.
+func (s *Status) WithShutdownDelay(d time.Duration) *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdownDelay = d
+	return s
+}
+
+// ShuttingDown reports whether Shutdown has been called.
+func (s *Status) ShuttingDown() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.shuttingDown
+}
+
+// Shutdown marks the application as shutting down, which makes /__/ready
+// report not ready straight away, waits for the shutdown delay and then runs
+// the shutdown hooks in order. Once ctx is done Shutdown returns without
+// waiting for the running hook, and the remaining hooks are skipped.
+// Background checks are stopped once the hooks have run. The errors of any
+// failed, overrunning or skipped hooks are returned. Only the first call to
+// Shutdown does anything, later calls return nil.
+func (s *Status) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.shuttingDown {
+		s.mu.Unlock()
+		return nil
+	}
+	s.shuttingDown = true
+	hooks, delay := s.shutdownHooks, s.shutdownDelay
+	s.mu.Unlock()
+
+	defer s.Stop()
+
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+		case <-t.C:
+		}
+	}
+
+	var errs []error
+	for _, h := range hooks {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook %q not run: %w", h.name, err))
+			continue
+		}
+		if err := h.run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook %q failed: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ShutdownOnSignal waits until the process receives one of the given signals,
+// SIGTERM or SIGINT if none are given, or until ctx is done. It then calls
+// Shutdown, allowing it up to timeout to complete, and returns its result.
+func (s *Status) ShutdownOnSignal(ctx context.Context, timeout time.Duration, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGTERM, os.Interrupt}
+	}
+	sigCtx, stop := signal.NotifyContext(ctx, signals...)
+	<-sigCtx.Done()
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return s.Shutdown(shutdownCtx)
+}
+
+type shutdownHook struct {
+	name string
+	hook func(ctx context.Context) error
+}
+
+// run calls the hook, giving up once ctx is done even if the hook ignores it.
+func (h shutdownHook) run(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("hook panicked: %v", r)
+			}
+			done <- err
+		}()
+		err = h.hook(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,121 @@
+package op
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	assert := assert.New(t)
+
+	var order []string
+	hc := NewStatus("my app", "app description").ReadyAlways()
+	hc.AddShutdownHook("server", func(ctx context.Context) error {
+		// Readiness must already be failing when hooks run.
+		assert.True(hc.ShuttingDown())
+		order = append(order, "server")
+		return nil
+	}).
+		AddShutdownHook("database", func(ctx context.Context) error {
+			order = append(order, "database")
+			return errors.New("close failed")
+		})
+
+	err := hc.Shutdown(context.Background())
+	assert.EqualError(err, `shutdown hook "database" failed: close failed`)
+	assert.Equal([]string{"server", "database"}, order)
+
+	result := hc.CheckReady(context.Background())
+	assert.False(result.Ready)
+	assert.True(result.ShuttingDown)
+
+	rec := httptest.NewRecorder()
+	newReadyHandler(hc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/__/ready", nil))
+	assert.Equal(http.StatusServiceUnavailable, rec.Code)
+	assert.Equal("shutting down\n", rec.Body.String())
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	ran := false
+	hc := NewStatus("my app", "app description").
+		WithShutdownDelay(time.Hour).
+		AddShutdownHook("server", func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := hc.Shutdown(ctx)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.False(ran)
+}
+
+func TestShutdownOnSignal(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewStatus("my app", "app description")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- hc.ShutdownOnSignal(ctx, time.Second)
+	}()
+
+	assert.False(hc.ShuttingDown())
+	cancel()
+	assert.NoError(<-done)
+	assert.True(hc.ShuttingDown())
+}
+
+func TestShutdownHookOverrunsDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	var ran int32
+	hc := NewStatus("my app", "app description").
+		AddShutdownHook("stuck", func(ctx context.Context) error {
+			<-release
+			return nil
+		}).
+		AddShutdownHook("after", func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := hc.Shutdown(ctx)
+	assert.Less(time.Since(start), time.Second, "Shutdown should return at the deadline")
+	assert.EqualError(err, "shutdown hook \"stuck\" failed: context deadline exceeded\nshutdown hook \"after\" not run: context deadline exceeded")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.Zero(atomic.LoadInt32(&ran))
+}
+
+func TestShutdownTwice(t *testing.T) {
+	assert := assert.New(t)
+
+	var ran int32
+	hc := NewStatus("my app", "app description").
+		AddShutdownHook("count", func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			return errors.New("failed")
+		})
+
+	assert.Error(hc.Shutdown(context.Background()))
+	assert.NoError(hc.Shutdown(context.Background()))
+	assert.Equal(int32(1), atomic.LoadInt32(&ran))
+}
@@ -0,0 +1,60 @@
+package op
+
+// AddStartupGate adds a named condition that must be completed, once, before
+// the application is considered started, for example "schema migrated".
+// Gates are reported on the /__/startup endpoint, intended for use as a
+// Kubernetes startup probe. Adding a gate that already exists has no effect.
+func (s *Status) AddStartupGate(name string) *Status {
+	s.startupMu.Lock()
+	defer s.startupMu.Unlock()
+
+	for _, g := range s.startupGates {
+		if g.name == name {
+			return s
+		}
+	}
+	s.startupGates = append(s.startupGates, &startupGate{name: name})
+	return s
+}
+
+// CompleteStartupGate marks the named startup gate as complete. Gates cannot
+// be un-completed, and completing an unknown gate has no effect.
+func (s *Status) CompleteStartupGate(name string) *Status {
+	s.startupMu.Lock()
+	defer s.startupMu.Unlock()
+
+	for _, g := range s.startupGates {
+		if g.name == name {
+			g.complete = true
+		}
+	}
+	return s
+}
+
+// Startup returns the current startup state of the application. It is
+// started once every startup gate has been completed.
+func (s *Status) Startup() StartupResult {
+	s.startupMu.Lock()
+	defer s.startupMu.Unlock()
+
+	sr := StartupResult{Started: true}
+	for _, g := range s.startupGates {
+		if !g.complete {
+			sr.Started = false
+			sr.Pending = append(sr.Pending, g.name)
+		}
+	}
+	return sr
+}
+
+type startupGate struct {
+	name     string
+	complete bool
+}
+
+// StartupResult represents the current startup state of an application,
+// along with any startup gates that are still pending.
+type StartupResult struct {
+	Started bool     `json:"started"`
+	Pending []string `json:"pending,omitempty"`
+}